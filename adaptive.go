@@ -0,0 +1,178 @@
+package newsapi
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// _defaultAdaptiveMin is the shortest adaptive poll interval, unless
+	// specified otherwise.
+	_defaultAdaptiveMin = time.Minute
+
+	// _defaultAdaptiveMax is the longest adaptive poll interval, unless
+	// specified otherwise.
+	_defaultAdaptiveMax = time.Hour
+
+	// _defaultAdaptiveFactor is the factor the adaptive poll interval is
+	// changed by, unless specified otherwise.
+	_defaultAdaptiveFactor = 2
+)
+
+// AdaptiveInterval adjusts the poll interval to the observed article
+// velocity. The interval is shortened while new articles keep arriving
+// and is backed off exponentially while nothing changes.
+type AdaptiveInterval struct {
+	// Min specifies the shortest interval. 1 minute is default.
+	Min time.Duration
+
+	// Max specifies the longest interval. 1 hour is default.
+	Max time.Duration
+
+	// Factor specifies the factor the interval is divided by after a
+	// poll that found new articles and multiplied by after a poll that
+	// did not. 2 is default; values not greater than 1 are ignored.
+	Factor float64
+}
+
+// next returns the interval to use after a poll that found the
+// specified number of new articles.
+func (ai *AdaptiveInterval) next(interval time.Duration, found int) time.Duration {
+	if found > 0 {
+		interval = time.Duration(float64(interval) / ai.factor())
+	} else {
+		interval = time.Duration(float64(interval) * ai.factor())
+	}
+
+	return ai.clamp(interval)
+}
+
+// clamp bounds the interval by the minimum and maximum intervals.
+func (ai *AdaptiveInterval) clamp(interval time.Duration) time.Duration {
+	switch {
+	case interval < ai.min():
+		return ai.min()
+	case interval > ai.max():
+		return ai.max()
+	}
+
+	return interval
+}
+
+// min returns the minimum interval or the default one.
+func (ai *AdaptiveInterval) min() time.Duration {
+	if ai.Min <= 0 {
+		return _defaultAdaptiveMin
+	}
+
+	return ai.Min
+}
+
+// max returns the maximum interval or the default one. It is never
+// shorter than the minimum interval.
+func (ai *AdaptiveInterval) max() time.Duration {
+	max := ai.Max
+	if max <= 0 {
+		max = _defaultAdaptiveMax
+	}
+
+	if max < ai.min() {
+		return ai.min()
+	}
+
+	return max
+}
+
+// factor returns the interval change factor or the default one.
+func (ai *AdaptiveInterval) factor() float64 {
+	if ai.Factor <= 1 {
+		return _defaultAdaptiveFactor
+	}
+
+	return ai.Factor
+}
+
+// PollBudget is a request budget shared by multiple watchers. Every
+// watcher is guaranteed an equal share of the budget, while the share
+// left unused by watchers polling less often, e.g. because they backed
+// off, is available to the rest.
+//
+// A poll that pages through several pages of articles uses a request
+// per page, so the interval after it is prolonged accordingly.
+//
+// The budget only spaces out polls and is not enforced strictly; use
+// WithDailyBudget to cap the number of requests sent by the client.
+type PollBudget struct {
+	mu       sync.Mutex
+	requests float64
+	period   time.Duration
+	shares   map[*pollShare]struct{}
+}
+
+// NewPollBudget creates a fresh instance of poll budget that allows the
+// specified number of requests per period. The number of requests must
+// be greater than zero.
+func NewPollBudget(requests uint, period time.Duration) *PollBudget {
+	return &PollBudget{
+		requests: float64(requests),
+		period:   period,
+		shares:   make(map[*pollShare]struct{}),
+	}
+}
+
+// join registers a watcher and returns its share of the budget.
+func (pb *PollBudget) join() *pollShare {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	ps := &pollShare{budget: pb}
+	pb.shares[ps] = struct{}{}
+
+	return ps
+}
+
+// pollShare is the share of the budget used by a single watcher.
+type pollShare struct {
+	budget *PollBudget
+
+	// rate specifies the number of requests per budget period the
+	// watcher currently polls at.
+	rate float64
+}
+
+// limit returns the interval, prolonged if needed to fit into the
+// budget when every poll sends the specified number of requests, and
+// records the resulting rate of the watcher.
+func (ps *pollShare) limit(interval time.Duration, requests int) time.Duration {
+	pb := ps.budget
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	available := pb.requests
+	for other := range pb.shares {
+		if other != ps {
+			available -= other.rate
+		}
+	}
+
+	if fair := pb.requests / float64(len(pb.shares)); available < fair {
+		available = fair
+	}
+
+	if floor := time.Duration(float64(requests) * float64(pb.period) / available); interval < floor {
+		interval = floor
+	}
+
+	ps.rate = float64(requests) * float64(pb.period) / float64(interval)
+
+	return interval
+}
+
+// leave removes the watcher from the budget.
+func (ps *pollShare) leave() {
+	ps.budget.mu.Lock()
+	defer ps.budget.mu.Unlock()
+
+	delete(ps.budget.shares, ps)
+}
@@ -0,0 +1,150 @@
+package newsapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AdaptiveInterval_next(t *testing.T) {
+	tests := map[string]struct {
+		Adaptive AdaptiveInterval
+		Interval time.Duration
+		Found    int
+		Result   time.Duration
+	}{
+		"Shortened": {
+			Interval: 10 * time.Minute,
+			Found:    3,
+			Result:   5 * time.Minute,
+		},
+		"Backed off": {
+			Interval: 10 * time.Minute,
+			Result:   20 * time.Minute,
+		},
+		"Custom factor": {
+			Adaptive: AdaptiveInterval{Factor: 1.5},
+			Interval: 10 * time.Minute,
+			Result:   15 * time.Minute,
+		},
+		"Bounded by default min": {
+			Interval: time.Minute,
+			Found:    1,
+			Result:   time.Minute,
+		},
+		"Bounded by default max": {
+			Interval: 45 * time.Minute,
+			Result:   time.Hour,
+		},
+		"Bounded by min": {
+			Adaptive: AdaptiveInterval{Min: 4 * time.Minute},
+			Interval: 5 * time.Minute,
+			Found:    1,
+			Result:   4 * time.Minute,
+		},
+		"Bounded by max": {
+			Adaptive: AdaptiveInterval{Max: 8 * time.Minute},
+			Interval: 5 * time.Minute,
+			Result:   8 * time.Minute,
+		},
+		"Max shorter than min": {
+			Adaptive: AdaptiveInterval{Min: 4 * time.Minute, Max: time.Minute},
+			Interval: 4 * time.Minute,
+			Result:   4 * time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.Result, test.Adaptive.next(test.Interval, test.Found))
+		})
+	}
+}
+
+func Test_PollBudget(t *testing.T) {
+	pb := NewPollBudget(60, time.Hour)
+
+	ps1 := pb.join()
+	assert.Equal(t, time.Minute, ps1.limit(time.Second, 1))
+	assert.Equal(t, 5*time.Minute, ps1.limit(5*time.Minute, 1))
+
+	// 12 requests per hour are used by the first watcher, 48 are left.
+	ps2 := pb.join()
+	assert.Equal(t, 75*time.Second, ps2.limit(time.Minute, 1))
+
+	// Fair share is guaranteed when the others use up the budget.
+	assert.Equal(t, 2*time.Minute, ps1.limit(time.Minute, 1))
+	assert.Equal(t, 2*time.Minute, ps2.limit(time.Minute, 1))
+
+	ps2.leave()
+	assert.Equal(t, time.Minute, ps1.limit(time.Second, 1))
+
+	// Every page retrieved by a poll counts as a request.
+	assert.Equal(t, 3*time.Minute, ps1.limit(time.Minute, 3))
+	assert.Equal(t, 9*time.Minute, ps1.limit(9*time.Minute, 3))
+
+	// 20 requests per hour are used by the first watcher, 40 are left.
+	ps3 := pb.join()
+	assert.Equal(t, 90*time.Second, ps3.limit(time.Minute, 1))
+}
+
+func Test_Watcher_Run_adaptive(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{now: start}
+	api := &pollAPI{
+		cancel: cancel,
+		responses: [][]Article{
+			{testArticle("1", start)},
+			{testArticle("2", start.Add(time.Minute))},
+			{testArticle("3", start.Add(2*time.Minute))},
+			{},
+			{},
+			{},
+			{},
+		},
+	}
+
+	// The other watcher uses up the budget, so only the fair share of
+	// 12 requests per hour is left.
+	budget := NewPollBudget(24, time.Hour)
+	other := budget.join()
+	other.limit(150*time.Second, 1)
+
+	w := &Watcher{
+		API:        api,
+		Everything: &EverythingParams{Query: "test"},
+		Interval:   8 * time.Minute,
+		Adaptive: &AdaptiveInterval{
+			Min: time.Minute,
+			Max: 30 * time.Minute,
+		},
+		Budget: budget,
+		Clock:  clock,
+	}
+
+	err := w.Run(ctx, func(Article) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Duration{
+		8 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+		10 * time.Minute,
+		20 * time.Minute,
+		30 * time.Minute,
+	}, clock.waits)
+	assert.Equal(t, map[*pollShare]struct{}{other: {}}, budget.shares)
+}
@@ -0,0 +1,309 @@
+package newsapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+var _ API = (*Client)(nil)
+
+// API retrieves articles and sources from newsapi. It is implemented by
+// Client and by decorators wrapping it, so consumers can depend on it
+// and substitute fakes in tests.
+type API interface {
+	// Everything retrieves articles by the provided parameters. The uint
+	// return value indicates the number of available articles.
+	Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error)
+
+	// TopHeadlines retrieves top headlines articles by the provided
+	// parameters. The uint return value indicates the number of
+	// available articles.
+	TopHeadlines(ctx context.Context, pr TopHeadlinesParams) ([]Article, uint, error)
+
+	// Sources retrieves available sources by the provided parameters.
+	Sources(ctx context.Context, pr SourceParams) ([]Source, error)
+}
+
+// Decorator wraps an API with additional behaviour.
+type Decorator func(api API) API
+
+// Decorate wraps the API with the decorators. The first decorator is the
+// outermost one, so it sees every call first.
+func Decorate(api API, decorators ...Decorator) API {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		api = decorators[i](api)
+	}
+
+	return api
+}
+
+// Logger is used to log API calls. *log.Logger satisfies it.
+type Logger interface {
+	// Printf logs a formatted message.
+	Printf(format string, v ...interface{})
+}
+
+// Logging creates a decorator that logs every API call along with its
+// duration and outcome.
+func Logging(logger Logger) Decorator {
+	return func(api API) API {
+		return &interceptedAPI{
+			api: api,
+			intercept: func(ctx context.Context, endpoint Endpoint, pr params, call func(context.Context) (int, error)) error {
+				start := time.Now()
+
+				n, err := call(ctx)
+				if err != nil {
+					logger.Printf("newsapi: %s %q failed in %s: %v", endpoint, pr.rawQuery(), time.Since(start), err)
+					return err
+				}
+
+				logger.Printf("newsapi: %s %q returned %d results in %s", endpoint, pr.rawQuery(), n, time.Since(start))
+
+				return nil
+			},
+		}
+	}
+}
+
+// RequestMetrics contains measurements of a single API call.
+type RequestMetrics struct {
+	// Endpoint specifies the called endpoint.
+	Endpoint Endpoint
+
+	// Duration specifies how long the call took.
+	Duration time.Duration
+
+	// Results specifies the number of returned articles or sources.
+	Results int
+
+	// Err specifies the error returned by the call, if any.
+	Err error
+}
+
+// MetricsRecorder records measurements of an API call, e.g. by updating
+// metric collectors. It must be safe for concurrent use.
+type MetricsRecorder func(m RequestMetrics)
+
+// Metrics creates a decorator that records measurements of every API
+// call.
+func Metrics(record MetricsRecorder) Decorator {
+	return func(api API) API {
+		return &interceptedAPI{
+			api: api,
+			intercept: func(ctx context.Context, endpoint Endpoint, pr params, call func(context.Context) (int, error)) error {
+				start := time.Now()
+				n, err := call(ctx)
+
+				record(RequestMetrics{
+					Endpoint: endpoint,
+					Duration: time.Since(start),
+					Results:  n,
+					Err:      err,
+				})
+
+				return err
+			},
+		}
+	}
+}
+
+// Retrying creates a decorator that retries failed API calls according
+// to the policy. Calls are classified by the newsapi error they return,
+// if any; parameter validation errors are never retried.
+func Retrying(rp RetryPolicy) Decorator {
+	return func(api API) API {
+		return &interceptedAPI{
+			api: api,
+			intercept: func(ctx context.Context, _ Endpoint, _ params, call func(context.Context) (int, error)) error {
+				return rp.call(ctx, func(ctx context.Context) error {
+					_, err := call(ctx)
+					return err
+				})
+			},
+		}
+	}
+}
+
+// Caching creates a decorator that stores successful results in the
+// cache, so identical calls are served without calling the wrapped API.
+// Results are cached for the default fresh durations of their endpoints:
+// 10 minutes for everything, 2 minutes for top headlines and a day for
+// sources.
+func Caching(cache Cache) Decorator {
+	return func(api API) API {
+		return &cachingAPI{
+			api:   api,
+			cache: cache,
+		}
+	}
+}
+
+// interceptor wraps a single API call. The call function calls the
+// wrapped API and returns the number of results.
+type interceptor func(ctx context.Context, endpoint Endpoint, pr params, call func(context.Context) (int, error)) error
+
+// interceptedAPI passes every call of the wrapped API through the
+// interceptor.
+type interceptedAPI struct {
+	api       API
+	intercept interceptor
+}
+
+// Everything intercepts the call of the wrapped API's Everything method.
+func (ia *interceptedAPI) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
+	var (
+		articles []Article
+		total    uint
+	)
+
+	err := ia.intercept(ctx, EndpointEverything, &pr, func(ctx context.Context) (int, error) {
+		var err error
+
+		articles, total, err = ia.api.Everything(ctx, pr)
+
+		return len(articles), err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// TopHeadlines intercepts the call of the wrapped API's TopHeadlines
+// method.
+func (ia *interceptedAPI) TopHeadlines(ctx context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	var (
+		articles []Article
+		total    uint
+	)
+
+	err := ia.intercept(ctx, EndpointTopHeadlines, &pr, func(ctx context.Context) (int, error) {
+		var err error
+
+		articles, total, err = ia.api.TopHeadlines(ctx, pr)
+
+		return len(articles), err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// Sources intercepts the call of the wrapped API's Sources method.
+func (ia *interceptedAPI) Sources(ctx context.Context, pr SourceParams) ([]Source, error) {
+	var sources []Source
+
+	err := ia.intercept(ctx, EndpointSources, &pr, func(ctx context.Context) (int, error) {
+		var err error
+
+		sources, err = ia.api.Sources(ctx, pr)
+
+		return len(sources), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// cachingAPI serves results of the wrapped API from the cache.
+type cachingAPI struct {
+	api   API
+	cache Cache
+}
+
+// articlesResult is a cached result of an articles endpoint.
+type articlesResult struct {
+	Articles []Article `json:"articles"`
+	Total    uint      `json:"total"`
+}
+
+// Everything returns the cached result or calls the wrapped API's
+// Everything method.
+func (ca *cachingAPI) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
+	var res articlesResult
+
+	err := ca.cached(EndpointEverything, &pr, &res, func() error {
+		var err error
+
+		res.Articles, res.Total, err = ca.api.Everything(ctx, pr)
+
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Articles, res.Total, nil
+}
+
+// TopHeadlines returns the cached result or calls the wrapped API's
+// TopHeadlines method.
+func (ca *cachingAPI) TopHeadlines(ctx context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	var res articlesResult
+
+	err := ca.cached(EndpointTopHeadlines, &pr, &res, func() error {
+		var err error
+
+		res.Articles, res.Total, err = ca.api.TopHeadlines(ctx, pr)
+
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Articles, res.Total, nil
+}
+
+// Sources returns the cached result or calls the wrapped API's Sources
+// method.
+func (ca *cachingAPI) Sources(ctx context.Context, pr SourceParams) ([]Source, error) {
+	var sources []Source
+
+	err := ca.cached(EndpointSources, &pr, &sources, func() error {
+		var err error
+
+		sources, err = ca.api.Sources(ctx, pr)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// cached decodes the cached result into the value. If the result is not
+// cached, the call function is invoked to fill the value, which is then
+// cached.
+func (ca *cachingAPI) cached(endpoint Endpoint, pr params, v interface{}, call func() error) error {
+	key := fmt.Sprintf("api:%s?%s", endpoint, pr.rawQuery())
+
+	if data, ok := ca.cache.Get(key); ok && json.Unmarshal(data, v) == nil {
+		return nil
+	}
+
+	if err := call(); err != nil {
+		return err
+	}
+
+	ttl := _defaultCacheTTLs[endpoint].Fresh
+	if ttl <= 0 {
+		return nil
+	}
+
+	if data, err := json.Marshal(v); err == nil {
+		ca.cache.Set(key, data, ttl)
+	}
+
+	return nil
+}
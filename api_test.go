@@ -0,0 +1,199 @@
+package newsapi
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPI is an API implementation that counts calls and returns
+// predefined results.
+type stubAPI struct {
+	mu       sync.Mutex
+	calls    int
+	articles []Article
+	sources  []Source
+	errs     []error
+}
+
+func (s *stubAPI) Everything(_ context.Context, _ EverythingParams) ([]Article, uint, error) {
+	if err := s.call(); err != nil {
+		return nil, 0, err
+	}
+
+	return s.articles, uint(len(s.articles)) * 10, nil
+}
+
+func (s *stubAPI) TopHeadlines(_ context.Context, _ TopHeadlinesParams) ([]Article, uint, error) {
+	if err := s.call(); err != nil {
+		return nil, 0, err
+	}
+
+	return s.articles, uint(len(s.articles)), nil
+}
+
+func (s *stubAPI) Sources(_ context.Context, _ SourceParams) ([]Source, error) {
+	if err := s.call(); err != nil {
+		return nil, err
+	}
+
+	return s.sources, nil
+}
+
+func (s *stubAPI) call() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+
+	if len(s.errs) == 0 {
+		return nil
+	}
+
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+
+	return err
+}
+
+func Test_Decorate(t *testing.T) {
+	var order []string
+
+	decorator := func(name string) Decorator {
+		return func(api API) API {
+			return &interceptedAPI{
+				api: api,
+				intercept: func(ctx context.Context, _ Endpoint, _ params, call func(context.Context) (int, error)) error {
+					order = append(order, name)
+					_, err := call(ctx)
+
+					return err
+				},
+			}
+		}
+	}
+
+	stub := &stubAPI{}
+	api := Decorate(stub, decorator("first"), decorator("second"))
+
+	_, err := api.Sources(context.Background(), SourceParams{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, 1, stub.calls)
+	assert.Equal(t, API(stub), Decorate(stub))
+}
+
+func Test_Logging(t *testing.T) {
+	var buf bytes.Buffer
+
+	stub := &stubAPI{
+		articles: []Article{{Title: "1"}, {Title: "2"}},
+		errs:     []error{nil, assert.AnError},
+	}
+	api := Logging(log.New(&buf, "", 0))(stub)
+
+	articles, total, err := api.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+	require.NoError(t, err)
+	assert.Equal(t, stub.articles, articles)
+	assert.Equal(t, uint(20), total)
+	assert.Contains(t, buf.String(), `newsapi: everything "q=bitcoin" returned 2 results in `)
+
+	buf.Reset()
+
+	_, err = api.Sources(context.Background(), SourceParams{})
+	assert.Equal(t, assert.AnError, err)
+	assert.Contains(t, buf.String(), `newsapi: top-headlines/sources "" failed in `)
+	assert.Contains(t, buf.String(), assert.AnError.Error())
+}
+
+func Test_Metrics(t *testing.T) {
+	var metrics []RequestMetrics
+
+	stub := &stubAPI{
+		articles: []Article{{Title: "1"}},
+		errs:     []error{nil, assert.AnError},
+	}
+	api := Metrics(func(m RequestMetrics) {
+		metrics = append(metrics, m)
+	})(stub)
+
+	_, _, err := api.TopHeadlines(context.Background(), TopHeadlinesParams{Country: CountryLatvia})
+	require.NoError(t, err)
+
+	_, _, err = api.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+	assert.Equal(t, assert.AnError, err)
+
+	require.Len(t, metrics, 2)
+	assert.Equal(t, EndpointTopHeadlines, metrics[0].Endpoint)
+	assert.Equal(t, 1, metrics[0].Results)
+	assert.NoError(t, metrics[0].Err)
+	assert.Equal(t, EndpointEverything, metrics[1].Endpoint)
+	assert.Equal(t, 0, metrics[1].Results)
+	assert.Equal(t, assert.AnError, metrics[1].Err)
+}
+
+func Test_Retrying(t *testing.T) {
+	stub := &stubAPI{
+		sources: []Source{{SourceID: SourceID{ID: "bbc"}}},
+		errs: []error{
+			&Error{HTTPCode: http.StatusInternalServerError, APICode: APICodeUnexpectedError},
+			nil,
+			&Error{HTTPCode: http.StatusUnauthorized, APICode: APICodeAPIKeyInvalid},
+		},
+	}
+	api := Retrying(RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+	})(stub)
+
+	sources, err := api.Sources(context.Background(), SourceParams{})
+	require.NoError(t, err)
+	assert.Equal(t, stub.sources, sources)
+	assert.Equal(t, 2, stub.calls)
+
+	_, _, err = api.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+	assert.Equal(t, 3, stub.calls)
+}
+
+func Test_Caching(t *testing.T) {
+	stub := &stubAPI{
+		articles: []Article{{Title: "1"}},
+		sources:  []Source{{SourceID: SourceID{ID: "bbc"}}},
+		errs:     []error{assert.AnError},
+	}
+	api := Caching(NewLRUCache(10))(stub)
+
+	_, _, err := api.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+	assert.Equal(t, assert.AnError, err)
+
+	for i := 0; i < 2; i++ {
+		articles, total, err := api.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+		require.NoError(t, err)
+		assert.Equal(t, stub.articles, articles)
+		assert.Equal(t, uint(10), total)
+
+		articles, total, err = api.TopHeadlines(context.Background(), TopHeadlinesParams{Query: "bitcoin"})
+		require.NoError(t, err)
+		assert.Equal(t, stub.articles, articles)
+		assert.Equal(t, uint(1), total)
+
+		sources, err := api.Sources(context.Background(), SourceParams{})
+		require.NoError(t, err)
+		assert.Equal(t, stub.sources, sources)
+	}
+
+	assert.Equal(t, 4, stub.calls)
+
+	_, _, err = api.Everything(context.Background(), EverythingParams{Query: "ethereum"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, stub.calls)
+}
@@ -0,0 +1,280 @@
+package newsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// _budgetPeriod is the length of a single budget period.
+const _budgetPeriod = 24 * time.Hour
+
+// BudgetCounter stores the number of requests spent within budget
+// periods. Implementations must be safe for concurrent use.
+type BudgetCounter interface {
+	// Count returns the number of requests spent within the period
+	// starting at the provided time.
+	Count(period time.Time) (uint, error)
+
+	// Take spends a single request within the period starting at the
+	// provided time. If the limit has already been reached, no request is
+	// spent and false is returned.
+	Take(period time.Time, limit uint) (bool, error)
+}
+
+// DailyBudget specifies the daily quota of outbound requests.
+type DailyBudget struct {
+	// Limit specifies the maximum number of requests per day.
+	Limit uint
+
+	// ResetOffset specifies the time of day, in UTC, at which the budget
+	// is reset. Midnight is default.
+	ResetOffset time.Duration
+
+	// Counter stores the number of spent requests. If left empty, an
+	// in-memory counter is used.
+	Counter BudgetCounter
+
+	// Wait specifies whether requests should wait until the budget is
+	// reset, instead of failing with ErrBudgetExhausted.
+	Wait bool
+}
+
+// WithDailyBudget limits the number of requests sent per day. Every
+// outbound request, including retries, is counted.
+func WithDailyBudget(db DailyBudget) ClientOption {
+	return func(c *Client) {
+		if db.Counter == nil {
+			db.Counter = NewMemoryCounter()
+		}
+
+		c.budget = &budget{
+			DailyBudget: db,
+			now:         time.Now,
+		}
+	}
+}
+
+// budget enforces the daily budget.
+type budget struct {
+	DailyBudget
+	now func() time.Time
+}
+
+// period returns the start of the budget period the provided time
+// belongs to.
+func (b *budget) period(t time.Time) time.Time {
+	return t.UTC().Add(-b.ResetOffset).Truncate(_budgetPeriod).Add(b.ResetOffset)
+}
+
+// take spends a single request of the current period. If the budget is
+// exhausted, take either waits for the next period or returns
+// ErrBudgetExhausted.
+func (b *budget) take(ctx context.Context) error {
+	for {
+		period := b.period(b.now())
+
+		ok, err := b.Counter.Take(period, b.Limit)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		if !b.Wait {
+			return ErrBudgetExhausted
+		}
+
+		timer := time.NewTimer(period.Add(_budgetPeriod).Sub(b.now()))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// MemoryCounter is a budget counter that stores the number of spent
+// requests in memory.
+type MemoryCounter struct {
+	mu     sync.Mutex
+	period time.Time
+	count  uint
+}
+
+// NewMemoryCounter creates a fresh instance of memory counter.
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{}
+}
+
+// Count returns the number of requests spent within the period.
+func (mc *MemoryCounter) Count(period time.Time) (uint, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if !mc.period.Equal(period) {
+		return 0, nil
+	}
+
+	return mc.count, nil
+}
+
+// Take spends a single request within the period, unless the limit has
+// been reached.
+func (mc *MemoryCounter) Take(period time.Time, limit uint) (bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if !mc.period.Equal(period) {
+		mc.period = period
+		mc.count = 0
+	}
+
+	if mc.count >= limit {
+		return false, nil
+	}
+
+	mc.count++
+
+	return true, nil
+}
+
+// FileCounter is a budget counter that persists the number of spent
+// requests to a JSON file, so that it survives restarts.
+type FileCounter struct {
+	mu     sync.Mutex
+	path   string
+	loaded bool
+	state  fileCounterState
+}
+
+// fileCounterState is the content of the file counter file.
+type fileCounterState struct {
+	Period time.Time `json:"period"`
+	Count  uint      `json:"count"`
+}
+
+// NewFileCounter creates a fresh instance of file counter that stores
+// its state at the provided path.
+func NewFileCounter(path string) *FileCounter {
+	return &FileCounter{
+		path: path,
+	}
+}
+
+// Count returns the number of requests spent within the period.
+func (fc *FileCounter) Count(period time.Time) (uint, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if err := fc.load(); err != nil {
+		return 0, err
+	}
+
+	if !fc.state.Period.Equal(period) {
+		return 0, nil
+	}
+
+	return fc.state.Count, nil
+}
+
+// Take spends a single request within the period, unless the limit has
+// been reached.
+func (fc *FileCounter) Take(period time.Time, limit uint) (bool, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if err := fc.load(); err != nil {
+		return false, err
+	}
+
+	state := fc.state
+	if !state.Period.Equal(period) {
+		state = fileCounterState{
+			Period: period,
+		}
+	}
+
+	if state.Count >= limit {
+		return false, nil
+	}
+
+	state.Count++
+
+	if err := fc.save(state); err != nil {
+		return false, err
+	}
+
+	fc.state = state
+
+	return true, nil
+}
+
+// load reads the state from the file, unless it has already been read.
+func (fc *FileCounter) load() error {
+	if fc.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(fc.path)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err = json.Unmarshal(data, &fc.state); err != nil {
+			return err
+		}
+	}
+
+	fc.loaded = true
+
+	return nil
+}
+
+// save atomically writes the state to the file.
+func (fc *FileCounter) save(state fileCounterState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(fc.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to the
+// provided path, so that the file is never left partially written.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err = os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package newsapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithDailyBudget(t *testing.T) {
+	c := &Client{}
+	WithDailyBudget(DailyBudget{Limit: 5})(c)
+
+	require.NotNil(t, c.budget)
+	assert.Equal(t, uint(5), c.budget.Limit)
+	assert.IsType(t, &MemoryCounter{}, c.budget.Counter)
+
+	counter := NewFileCounter("test")
+	WithDailyBudget(DailyBudget{Limit: 5, Counter: counter})(c)
+	assert.Equal(t, counter, c.budget.Counter)
+}
+
+func Test_budget_period(t *testing.T) {
+	b := &budget{}
+	assert.Equal(
+		t,
+		time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC),
+		b.period(time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)),
+	)
+	assert.Equal(
+		t,
+		time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC),
+		b.period(time.Date(2022, 02, 23, 1, 22, 22, 0, time.FixedZone("test", 3600*2))),
+	)
+
+	b.ResetOffset = 8 * time.Hour
+	assert.Equal(
+		t,
+		time.Date(2022, 02, 22, 8, 0, 0, 0, time.UTC),
+		b.period(time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)),
+	)
+	assert.Equal(
+		t,
+		time.Date(2022, 02, 21, 8, 0, 0, 0, time.UTC),
+		b.period(time.Date(2022, 02, 22, 7, 22, 22, 0, time.UTC)),
+	)
+}
+
+func Test_budget_take(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	b := &budget{
+		DailyBudget: DailyBudget{
+			Limit:   2,
+			Counter: NewMemoryCounter(),
+		},
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	require.NoError(t, b.take(context.Background()))
+	require.NoError(t, b.take(context.Background()))
+	assert.Equal(t, ErrBudgetExhausted, b.take(context.Background()))
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, b.take(context.Background()))
+
+	b.Wait = true
+	require.NoError(t, b.take(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, b.take(ctx))
+
+	b.now = func() time.Time {
+		now = now.Add(12 * time.Hour)
+		return now
+	}
+
+	require.NoError(t, b.take(context.Background()))
+}
+
+func Test_MemoryCounter(t *testing.T) {
+	period := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	mc := NewMemoryCounter()
+
+	count, err := mc.Count(period)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	for i := 0; i < 2; i++ {
+		ok, err := mc.Take(period, 2)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	ok, err := mc.Take(period, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	count, err = mc.Count(period)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+
+	next := period.Add(_budgetPeriod)
+
+	count, err = mc.Count(next)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	ok, err = mc.Take(next, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func Test_FileCounter(t *testing.T) {
+	period := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "budget.json")
+
+	fc := NewFileCounter(path)
+
+	count, err := fc.Count(period)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	for i := 0; i < 2; i++ {
+		ok, err := fc.Take(period, 2)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	fc = NewFileCounter(path)
+
+	count, err = fc.Count(period)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+
+	ok, err := fc.Take(period, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = fc.Take(period.Add(_budgetPeriod), 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	fc = NewFileCounter(path)
+
+	count, err = fc.Count(period.Add(_budgetPeriod))
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), count)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err = NewFileCounter(path).Take(period, 2)
+	assert.Error(t, err)
+
+	_, err = NewFileCounter(filepath.Join(path, "test")).Take(period, 2)
+	assert.Error(t, err)
+}
+
+func Test_Client_get_budget(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRateLimit(10, time.Second),
+		WithDailyBudget(DailyBudget{Limit: 1}),
+	)
+
+	transport.RegisterResponder(
+		http.MethodGet,
+		"test/top-headlines/sources",
+		httpmock.NewStringResponder(http.StatusOK, `{"status":"ok"}`),
+	)
+
+	_, err := client.Sources(context.Background(), SourceParams{})
+	require.NoError(t, err)
+
+	_, err = client.Sources(context.Background(), SourceParams{})
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+}
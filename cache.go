@@ -0,0 +1,246 @@
+package newsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// _defaultCacheTTLs contains default cache durations of every endpoint.
+var _defaultCacheTTLs = map[Endpoint]CacheTTL{
+	EndpointEverything: {
+		Fresh: 10 * time.Minute,
+	},
+	EndpointTopHeadlines: {
+		Fresh: 2 * time.Minute,
+	},
+	EndpointSources: {
+		Fresh: 24 * time.Hour,
+		Stale: 24 * time.Hour,
+	},
+}
+
+// Cache stores raw newsapi responses. Implementations must be safe for
+// concurrent use. Caching is best-effort, so implementations should
+// treat storage failures as cache misses.
+type Cache interface {
+	// Get returns the value stored by the key. False is returned when
+	// the value is not found or it has expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the value by the key for the provided duration.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CacheTTL specifies how long responses of an endpoint are cached.
+type CacheTTL struct {
+	// Fresh specifies how long a cached response is served without
+	// sending a request to newsapi. Zero disables caching.
+	Fresh time.Duration
+
+	// Stale specifies how long after becoming stale a cached response
+	// is still served, while it is revalidated in the background.
+	Stale time.Duration
+}
+
+// WithCache sets the cache in which successful responses are stored.
+// Responses are cached by the base URL, the API key, the endpoint and
+// the query parameters, so identical requests are served from the cache,
+// while clients sharing it do not get each other's responses. Only a
+// hash of the API key is included in the cache keys.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.responseCache().store = cache
+	}
+}
+
+// WithCacheTTL sets cache durations of the endpoint. By default,
+// everything responses are cached for 10 minutes, top headlines responses
+// for 2 minutes and sources responses for a day, after which they are
+// served stale for another day.
+func WithCacheTTL(endpoint Endpoint, ttl CacheTTL) ClientOption {
+	return func(c *Client) {
+		c.responseCache().ttls[endpoint] = ttl
+	}
+}
+
+// responseCache returns the response cache of the client, creating it if
+// it does not exist yet.
+func (c *Client) responseCache() *responseCache {
+	if c.cache == nil {
+		c.cache = newResponseCache()
+	}
+
+	return c.cache
+}
+
+// getCached returns the cached response of the request, sending the
+// request only if the response is not cached or has expired. Stale
+// responses are returned immediately and revalidated in the background.
+func (c *Client) getCached(endpoint Endpoint, req *http.Request) (*http.Response, error) {
+	ttl := c.cache.ttl(endpoint)
+	if ttl.Fresh <= 0 {
+		return c.do(req)
+	}
+
+	key := c.cacheKey(endpoint, req)
+
+	if entry, ok := c.cache.get(key); ok {
+		age := c.cache.now().Sub(entry.StoredAt)
+
+		switch {
+		case age <= ttl.Fresh:
+			return cachedResponse(entry.Body), nil
+		case age <= ttl.Fresh+ttl.Stale:
+			if c.cache.startRevalidation(key) {
+				req := req.Clone(context.Background())
+
+				go func() {
+					defer c.cache.finishRevalidation(key)
+
+					resp, err := c.fetchAndCache(req, key, ttl)
+					if err == nil {
+						resp.Body.Close()
+					}
+				}()
+			}
+
+			return cachedResponse(entry.Body), nil
+		}
+	}
+
+	return c.fetchAndCache(req, key, ttl)
+}
+
+// cacheKey returns the key the response of the request is cached by.
+func (c *Client) cacheKey(endpoint Endpoint, req *http.Request) string {
+	return shortHash(c.apiKey) + " " + c.baseURL + string(endpoint) + "?" + req.URL.RawQuery
+}
+
+// fetchAndCache sends the request and caches the response if it is
+// successful.
+func (c *Client) fetchAndCache(req *http.Request, key string, ttl CacheTTL) (*http.Response, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.set(key, body, ttl)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// cachedResponse creates a successful response with the cached body.
+func cachedResponse(body []byte) *http.Response {
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+// responseCache caches responses in the underlying store.
+type responseCache struct {
+	store Cache
+	ttls  map[Endpoint]CacheTTL
+	now   func() time.Time
+
+	mu           sync.Mutex
+	revalidating map[string]struct{}
+}
+
+// cacheEntry is a cached response body along with the time it was
+// stored at.
+type cacheEntry struct {
+	StoredAt time.Time `json:"storedAt"`
+	Body     []byte    `json:"body"`
+}
+
+// newResponseCache creates a fresh instance of response cache with the
+// default cache durations.
+func newResponseCache() *responseCache {
+	rc := &responseCache{
+		ttls:         make(map[Endpoint]CacheTTL),
+		now:          time.Now,
+		revalidating: make(map[string]struct{}),
+	}
+
+	for endpoint, ttl := range _defaultCacheTTLs {
+		rc.ttls[endpoint] = ttl
+	}
+
+	return rc
+}
+
+// ttl returns cache durations of the endpoint.
+func (rc *responseCache) ttl(endpoint Endpoint) CacheTTL {
+	return rc.ttls[endpoint]
+}
+
+// get retrieves the cache entry by the key.
+func (rc *responseCache) get(key string) (cacheEntry, bool) {
+	data, ok := rc.store.Get(key)
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// set stores the body by the key for as long as it can be served.
+func (rc *responseCache) set(key string, body []byte, ttl CacheTTL) {
+	data, err := json.Marshal(cacheEntry{
+		StoredAt: rc.now(),
+		Body:     body,
+	})
+	if err != nil {
+		return
+	}
+
+	rc.store.Set(key, data, ttl.Fresh+ttl.Stale)
+}
+
+// startRevalidation marks the key as being revalidated. False is returned
+// if the key is already being revalidated.
+func (rc *responseCache) startRevalidation(key string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if _, ok := rc.revalidating[key]; ok {
+		return false
+	}
+
+	rc.revalidating[key] = struct{}{}
+
+	return true
+}
+
+// finishRevalidation unmarks the key as being revalidated.
+func (rc *responseCache) finishRevalidation(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.revalidating, key)
+}
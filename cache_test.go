@@ -0,0 +1,233 @@
+package newsapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithCache(t *testing.T) {
+	c := &Client{}
+	cache := NewLRUCache(10)
+	WithCache(cache)(c)
+
+	require.NotNil(t, c.cache)
+	assert.Equal(t, cache, c.cache.store)
+	assert.Equal(t, _defaultCacheTTLs[EndpointSources], c.cache.ttl(EndpointSources))
+}
+
+func Test_WithCacheTTL(t *testing.T) {
+	c := &Client{}
+	WithCacheTTL(EndpointEverything, CacheTTL{Fresh: time.Second})(c)
+
+	require.NotNil(t, c.cache)
+	assert.Nil(t, c.cache.store)
+	assert.Equal(t, CacheTTL{Fresh: time.Second}, c.cache.ttl(EndpointEverything))
+	assert.Equal(t, _defaultCacheTTLs[EndpointSources], c.cache.ttl(EndpointSources))
+}
+
+func Test_Client_getCached(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "test/everything", func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+
+		if req.URL.Query().Get("q") == "fail" {
+			return httpmock.NewStringResponse(
+				http.StatusBadRequest,
+				`{"status":"error","code":"parameterInvalid","message":"bad thing"}`,
+			), nil
+		}
+
+		return httpmock.NewStringResponse(
+			http.StatusOK,
+			`{"status":"ok","totalResults":1,"articles":[{"title":"`+now.String()+`"}]}`,
+		), nil
+	})
+
+	store := NewLRUCache(10)
+	store.now = func() time.Time {
+		return now
+	}
+
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithCache(store),
+		WithCacheTTL(EndpointEverything, CacheTTL{
+			Fresh: time.Minute,
+			Stale: time.Minute,
+		}),
+	)
+	client.cache.now = store.now
+
+	everything := func(query string) string {
+		articles, _, err := client.Everything(context.Background(), EverythingParams{Query: query})
+		if err != nil {
+			return err.Error()
+		}
+
+		return articles[0].Title
+	}
+
+	callCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return calls
+	}
+
+	first := now.String()
+
+	assert.Equal(t, first, everything("123"))
+	assert.Equal(t, 1, callCount())
+
+	// Fresh response is served from the cache.
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, first, everything("123"))
+	assert.Equal(t, 1, callCount())
+
+	// Different parameters are not served from the cache.
+	assert.Equal(t, now.String(), everything("321"))
+	assert.Equal(t, 2, callCount())
+
+	// Stale response is served and revalidated in the background.
+	now = now.Add(time.Minute)
+	assert.Equal(t, first, everything("123"))
+	assert.Eventually(t, func() bool {
+		return callCount() == 3
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		client.cache.mu.Lock()
+		defer client.cache.mu.Unlock()
+
+		return len(client.cache.revalidating) == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, now.String(), everything("123"))
+	assert.Equal(t, 3, callCount())
+
+	// Expired response is not served.
+	revalidated := now.String()
+	now = now.Add(3 * time.Minute)
+	assert.NotEqual(t, revalidated, everything("123"))
+	assert.Equal(t, 4, callCount())
+
+	// Unsuccessful responses are not cached.
+	everything("fail")
+	everything("fail")
+	assert.Equal(t, 6, callCount())
+}
+
+func Test_Client_getCached_key(t *testing.T) {
+	store := NewLRUCache(10)
+
+	newClient := func(apiKey, baseURL string) (*Client, *httpmock.MockTransport) {
+		transport := httpmock.NewMockTransport()
+		transport.RegisterResponder(
+			http.MethodGet,
+			baseURL+"top-headlines/sources",
+			httpmock.NewStringResponder(http.StatusOK, `{"status":"ok"}`),
+		)
+
+		return NewClient(
+			apiKey,
+			WithBaseURL(baseURL),
+			WithHTTPClient(&http.Client{Transport: transport}),
+			WithCache(store),
+		), transport
+	}
+
+	for _, cfg := range []struct {
+		APIKey  string
+		BaseURL string
+		Calls   int
+	}{
+		{APIKey: "777", BaseURL: "test/", Calls: 1},
+		{APIKey: "777", BaseURL: "local/", Calls: 1},
+		{APIKey: "secret", BaseURL: "test/", Calls: 1},
+		{APIKey: "777", BaseURL: "test/", Calls: 0},
+	} {
+		client, transport := newClient(cfg.APIKey, cfg.BaseURL)
+
+		_, err := client.Sources(context.Background(), SourceParams{})
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Calls, transport.GetTotalCallCount(), cfg)
+	}
+
+	assert.Equal(t, 3, store.Len())
+
+	client, _ := newClient("secret", "test/")
+	req, err := http.NewRequest(http.MethodGet, "test/everything?q=123", http.NoBody)
+	require.NoError(t, err)
+	assert.NotContains(t, client.cacheKey(EndpointEverything, req), "secret")
+}
+
+func Test_Client_getCached_disabled(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(
+		http.MethodGet,
+		"test/top-headlines/sources",
+		httpmock.NewStringResponder(http.StatusOK, `{"status":"ok"}`),
+	)
+
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithCache(NewLRUCache(10)),
+		WithCacheTTL(EndpointSources, CacheTTL{}),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Sources(context.Background(), SourceParams{})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
+
+func Test_responseCache_get(t *testing.T) {
+	rc := newResponseCache()
+	rc.store = NewLRUCache(10)
+
+	_, ok := rc.get("test")
+	assert.False(t, ok)
+
+	rc.store.Set("test", []byte("{"), time.Minute)
+
+	_, ok = rc.get("test")
+	assert.False(t, ok)
+
+	rc.set("test", []byte("123"), CacheTTL{Fresh: time.Minute})
+
+	entry, ok := rc.get("test")
+	require.True(t, ok)
+	assert.Equal(t, []byte("123"), entry.Body)
+}
+
+func Test_responseCache_startRevalidation(t *testing.T) {
+	rc := newResponseCache()
+
+	assert.True(t, rc.startRevalidation("test"))
+	assert.False(t, rc.startRevalidation("test"))
+	assert.True(t, rc.startRevalidation("test2"))
+
+	rc.finishRevalidation("test")
+	assert.True(t, rc.startRevalidation("test"))
+}
@@ -0,0 +1,174 @@
+package newsapi
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LRUCache is an in-memory cache that holds a limited number of values,
+// evicting the least recently used ones first.
+type LRUCache struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// lruEntry is a single value stored in LRU cache.
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates a fresh instance of LRU cache that holds up to the
+// provided number of values.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		now:      time.Now,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored by the key.
+func (lc *LRUCache) Get(key string) ([]byte, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	elem, ok := lc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !lc.now().Before(entry.expiresAt) {
+		lc.order.Remove(elem)
+		delete(lc.entries, key)
+
+		return nil, false
+	}
+
+	lc.order.MoveToFront(elem)
+
+	return entry.value, true
+}
+
+// Set stores the value by the key for the provided duration. If the
+// cache is full, the least recently used value is evicted.
+func (lc *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.capacity <= 0 {
+		return
+	}
+
+	entry := &lruEntry{
+		key:       key,
+		value:     value,
+		expiresAt: lc.now().Add(ttl),
+	}
+
+	if elem, ok := lc.entries[key]; ok {
+		elem.Value = entry
+		lc.order.MoveToFront(elem)
+
+		return
+	}
+
+	lc.entries[key] = lc.order.PushFront(entry)
+
+	for lc.order.Len() > lc.capacity {
+		elem := lc.order.Back()
+		lc.order.Remove(elem)
+		delete(lc.entries, elem.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of values held by the cache, including the
+// expired ones that have not been evicted yet.
+func (lc *LRUCache) Len() int {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.order.Len()
+}
+
+// FileCache is a cache that stores every value in a separate file of
+// the directory, so that cached values survive restarts.
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+// fileCacheEntry is the content of a single file cache file.
+type fileCacheEntry struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Value     []byte    `json:"value"`
+}
+
+// NewFileCache creates a fresh instance of file cache that stores values
+// in the provided directory. The directory is created if it does not
+// exist.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// Get returns the value stored by the key. Expired values are removed.
+func (fc *FileCache) Get(key string) ([]byte, bool) {
+	path := fc.path(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry fileCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if !fc.now().Before(entry.ExpiresAt) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+// Set stores the value by the key for the provided duration.
+func (fc *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := json.Marshal(fileCacheEntry{
+		ExpiresAt: fc.now().Add(ttl),
+		Value:     value,
+	})
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(fc.dir, 0o755); err != nil {
+		return
+	}
+
+	// Caching is best-effort, so write errors are ignored.
+	_ = writeFileAtomic(fc.path(key), data)
+}
+
+// path returns the path of the file in which the value of the key is
+// stored.
+func (fc *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(fc.dir, hex.EncodeToString(sum[:])+".json")
+}
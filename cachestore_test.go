@@ -0,0 +1,97 @@
+package newsapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LRUCache(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	lc := NewLRUCache(2)
+	lc.now = func() time.Time {
+		return now
+	}
+
+	_, ok := lc.Get("1")
+	assert.False(t, ok)
+
+	lc.Set("1", []byte("1"), time.Minute)
+	lc.Set("2", []byte("2"), time.Minute)
+
+	value, ok := lc.Get("1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	// "2" is the least recently used value.
+	lc.Set("3", []byte("3"), time.Minute)
+	assert.Equal(t, 2, lc.Len())
+
+	_, ok = lc.Get("2")
+	assert.False(t, ok)
+
+	lc.Set("1", []byte("11"), 2*time.Minute)
+
+	value, ok = lc.Get("1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("11"), value)
+
+	now = now.Add(time.Minute)
+
+	_, ok = lc.Get("3")
+	assert.False(t, ok)
+	assert.Equal(t, 1, lc.Len())
+
+	_, ok = lc.Get("1")
+	assert.True(t, ok)
+
+	lc = NewLRUCache(0)
+	lc.Set("1", []byte("1"), time.Minute)
+	assert.Equal(t, 0, lc.Len())
+}
+
+func Test_FileCache(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	fc := NewFileCache(dir)
+	fc.now = func() time.Time {
+		return now
+	}
+
+	_, ok := fc.Get("1")
+	assert.False(t, ok)
+
+	fc.Set("1", []byte("1"), time.Minute)
+
+	value, ok := fc.Get("1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+
+	_, ok = fc.Get("1")
+	assert.False(t, ok)
+
+	_, err := os.Stat(fc.path("1"))
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, os.WriteFile(fc.path("2"), []byte("{"), 0o600))
+
+	_, ok = fc.Get("2")
+	assert.False(t, ok)
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	fc = NewFileCache(file)
+	fc.Set("1", []byte("1"), time.Minute)
+
+	_, ok = fc.Get("1")
+	assert.False(t, ok)
+}
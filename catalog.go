@@ -0,0 +1,423 @@
+package newsapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// _defaultCatalogTTL is the age after which a source catalog is stale,
+// unless specified otherwise.
+const _defaultCatalogTTL = 24 * time.Hour
+
+// SourceCatalog is an offline index of sources. It can be loaded from
+// newsapi, snapshotted to and from JSON and queried by id, domain, name
+// and filters. The zero value is an empty catalog; API must be set to
+// refresh it.
+type SourceCatalog struct {
+	// API is used to refresh the catalog.
+	API API
+
+	// Params specifies the filters of sources loaded into the catalog.
+	Params SourceParams
+
+	// TTL specifies the age after which the catalog is refreshed by
+	// RefreshIfStale. 24 hours is default.
+	TTL time.Duration
+
+	mu        sync.RWMutex
+	now       func() time.Time
+	fetchedAt time.Time
+	sources   []Source
+	byID      map[string]int
+	byDomain  map[string]int
+}
+
+// SourcedArticle is an article along with the full record of its source.
+type SourcedArticle struct {
+	Article
+
+	// SourceDetails specifies the full record of the article source. It
+	// is nil if the source is not in the catalog.
+	SourceDetails *Source `json:"sourceDetails"`
+}
+
+// catalogSnapshot is the JSON representation of a source catalog.
+type catalogSnapshot struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Sources   []Source  `json:"sources"`
+}
+
+// NewSourceCatalog creates a fresh instance of source catalog that
+// contains the provided sources.
+func NewSourceCatalog(sources []Source) *SourceCatalog {
+	sc := &SourceCatalog{}
+	sc.set(sources, sc.clock())
+
+	return sc
+}
+
+// LoadSourceCatalog creates a fresh instance of source catalog and loads
+// the sources matching the parameters into it.
+func LoadSourceCatalog(ctx context.Context, api API, pr SourceParams) (*SourceCatalog, error) {
+	sc := &SourceCatalog{
+		API:    api,
+		Params: pr,
+	}
+
+	if err := sc.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// Refresh reloads the sources from newsapi.
+func (sc *SourceCatalog) Refresh(ctx context.Context) error {
+	sources, err := sc.API.Sources(ctx, sc.Params)
+	if err != nil {
+		return err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.set(sources, sc.clock())
+
+	return nil
+}
+
+// RefreshIfStale reloads the sources from newsapi if the catalog is
+// older than its TTL or was never loaded.
+func (sc *SourceCatalog) RefreshIfStale(ctx context.Context) error {
+	if !sc.Stale() {
+		return nil
+	}
+
+	return sc.Refresh(ctx)
+}
+
+// Stale checks if the catalog is older than its TTL or was never loaded.
+func (sc *SourceCatalog) Stale() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	ttl := sc.TTL
+	if ttl <= 0 {
+		ttl = _defaultCatalogTTL
+	}
+
+	return sc.fetchedAt.IsZero() || sc.clock().Sub(sc.fetchedAt) >= ttl
+}
+
+// FetchedAt returns the time the sources were loaded at.
+func (sc *SourceCatalog) FetchedAt() time.Time {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.fetchedAt
+}
+
+// Sources returns all sources of the catalog.
+func (sc *SourceCatalog) Sources() []Source {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return append([]Source(nil), sc.sources...)
+}
+
+// ByID returns the source with the id.
+func (sc *SourceCatalog) ByID(id string) (Source, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	i, ok := sc.byID[id]
+	if !ok {
+		return Source{}, false
+	}
+
+	return sc.sources[i], true
+}
+
+// ByDomain returns the source whose url has the domain. Either a domain
+// or a full url, e.g. of an article, can be provided. Subdomains match
+// their parent domains, and "www." prefix is ignored.
+func (sc *SourceCatalog) ByDomain(domainOrURL string) (Source, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	i, ok := sc.domainIndex(domainOrURL)
+	if !ok {
+		return Source{}, false
+	}
+
+	return sc.sources[i], true
+}
+
+// Search returns the sources whose names match the provided name
+// loosely, best matches first. Case, punctuation and small typos are
+// ignored.
+func (sc *SourceCatalog) Search(name string) []Source {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	needle := normalizeName(name)
+	if needle == "" {
+		return nil
+	}
+
+	type candidate struct {
+		score int
+		src   Source
+	}
+
+	var candidates []candidate
+
+	for _, src := range sc.sources {
+		if score, ok := nameScore(needle, normalizeName(src.Name)); ok {
+			candidates = append(candidates, candidate{score: score, src: src})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+
+		return candidates[i].src.Name < candidates[j].src.Name
+	})
+
+	res := make([]Source, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.src)
+	}
+
+	return res
+}
+
+// Filter returns the sources that match one of the values of every
+// filter of the parameters.
+func (sc *SourceCatalog) Filter(pr SourceParams) []Source {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	var res []Source
+
+	for _, src := range sc.sources {
+		if pr.match(src) {
+			res = append(res, src)
+		}
+	}
+
+	return res
+}
+
+// Enrich pairs the articles with the full records of their sources. The
+// source is looked up by its id, then by the domain of the article url
+// and then by its exact name.
+func (sc *SourceCatalog) Enrich(articles []Article) []SourcedArticle {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	res := make([]SourcedArticle, 0, len(articles))
+
+	for _, a := range articles {
+		sa := SourcedArticle{Article: a}
+
+		if i, ok := sc.articleIndex(a); ok {
+			src := sc.sources[i]
+			sa.SourceDetails = &src
+		}
+
+		res = append(res, sa)
+	}
+
+	return res
+}
+
+// MarshalJSON encodes the sources of the catalog along with the time
+// they were loaded at.
+func (sc *SourceCatalog) MarshalJSON() ([]byte, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return json.Marshal(catalogSnapshot{
+		FetchedAt: sc.fetchedAt,
+		Sources:   sc.sources,
+	})
+}
+
+// UnmarshalJSON replaces the sources of the catalog with the ones of the
+// snapshot.
+func (sc *SourceCatalog) UnmarshalJSON(data []byte) error {
+	var snap catalogSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.set(snap.Sources, snap.FetchedAt)
+
+	return nil
+}
+
+// set replaces the sources and rebuilds the indexes.
+func (sc *SourceCatalog) set(sources []Source, fetchedAt time.Time) {
+	sc.sources = append([]Source(nil), sources...)
+	sc.fetchedAt = fetchedAt
+	sc.byID = make(map[string]int, len(sources))
+	sc.byDomain = make(map[string]int, len(sources))
+
+	for i, src := range sc.sources {
+		if _, ok := sc.byID[src.ID]; !ok && src.ID != "" {
+			sc.byID[src.ID] = i
+		}
+
+		if domain := domainOf(src.URL); domain != "" {
+			if _, ok := sc.byDomain[domain]; !ok {
+				sc.byDomain[domain] = i
+			}
+		}
+	}
+}
+
+// domainIndex returns the index of the source with the domain of the
+// url or of any of its parent domains.
+func (sc *SourceCatalog) domainIndex(rawURL string) (int, bool) {
+	domain := domainOf(rawURL)
+
+	for strings.Contains(domain, ".") {
+		if i, ok := sc.byDomain[domain]; ok {
+			return i, true
+		}
+
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+
+	return 0, false
+}
+
+// articleIndex returns the index of the article source.
+func (sc *SourceCatalog) articleIndex(a Article) (int, bool) {
+	if i, ok := sc.byID[a.Source.ID]; ok && a.Source.ID != "" {
+		return i, true
+	}
+
+	if i, ok := sc.domainIndex(a.URL); ok {
+		return i, true
+	}
+
+	if a.Source.Name == "" {
+		return 0, false
+	}
+
+	for i, src := range sc.sources {
+		if strings.EqualFold(src.Name, a.Source.Name) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// clock returns the current time.
+func (sc *SourceCatalog) clock() time.Time {
+	if sc.now == nil {
+		return time.Now()
+	}
+
+	return sc.now()
+}
+
+// domainOf returns the lowercase host of the url without "www." prefix.
+// Urls without a scheme are accepted too.
+func domainOf(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// normalizeName returns the lowercase words of the name consisting of
+// letters and digits, separated by single spaces.
+func normalizeName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// nameScore scores how well the normalized name matches the normalized
+// needle; lower is better. False is returned if it does not match.
+func nameScore(needle, name string) (int, bool) {
+	switch {
+	case name == needle:
+		return 0, true
+	case strings.HasPrefix(name, needle):
+		return 1, true
+	case strings.Contains(name, needle):
+		return 2, true
+	}
+
+	maxDist := len([]rune(needle)) / 4
+	if maxDist < 1 {
+		maxDist = 1
+	}
+
+	if dist := fuzzyDistance(needle, name); dist <= maxDist {
+		return 2 + dist, true
+	}
+
+	return 0, false
+}
+
+// fuzzyDistance returns the smallest edit distance between the needle
+// and any substring of the text.
+func fuzzyDistance(needle, text string) int {
+	rn, rt := []rune(needle), []rune(text)
+
+	// Rows are indexed by text positions, so that a match can start
+	// anywhere in the text at no cost.
+	prev := make([]int, len(rt)+1)
+	cur := make([]int, len(rt)+1)
+
+	for i := 1; i <= len(rn); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(rt); j++ {
+			cost := 1
+			if rn[i-1] == rt[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return minInt(prev[0], prev[1:]...)
+}
+
+// minInt returns the smallest of the values.
+func minInt(v int, vs ...int) int {
+	for _, x := range vs {
+		if x < v {
+			v = x
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,220 @@
+package newsapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCatalogSources() []Source {
+	return []Source{
+		{
+			SourceID: SourceID{ID: "bbc-news", Name: "BBC News"},
+			URL:      "http://www.bbc.co.uk/news",
+			Category: CategoryGeneral,
+			Language: LanguageEnglish,
+			Country:  CountryUnitedKingdom,
+		},
+		{
+			SourceID: SourceID{ID: "bbc-sport", Name: "BBC Sport"},
+			URL:      "http://www.bbc.co.uk/sport",
+			Category: CategorySports,
+			Language: LanguageEnglish,
+			Country:  CountryUnitedKingdom,
+		},
+		{
+			SourceID: SourceID{ID: "cnn", Name: "CNN"},
+			URL:      "http://us.cnn.com",
+			Category: CategoryGeneral,
+			Language: LanguageEnglish,
+			Country:  CountryUnitedStates,
+		},
+		{
+			SourceID: SourceID{ID: "der-tagesspiegel", Name: "Der Tagesspiegel"},
+			URL:      "https://www.tagesspiegel.de",
+			Category: CategoryGeneral,
+			Language: LanguageGerman,
+			Country:  CountryGermany,
+		},
+		{
+			SourceID: SourceID{ID: "the-wall-street-journal", Name: "The Wall Street Journal"},
+			URL:      "https://www.wsj.com",
+			Category: CategoryBusiness,
+			Language: LanguageEnglish,
+			Country:  CountryUnitedStates,
+		},
+	}
+}
+
+func Test_LoadSourceCatalog(t *testing.T) {
+	now := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	api := &stubAPI{sources: testCatalogSources()}
+
+	sc, err := LoadSourceCatalog(context.Background(), api, SourceParams{})
+	require.NoError(t, err)
+	assert.Equal(t, testCatalogSources(), sc.Sources())
+	assert.False(t, sc.Stale())
+	assert.Equal(t, 1, api.calls)
+
+	sc.now = func() time.Time { return now }
+	sc.fetchedAt = now.Add(-time.Hour)
+	sc.TTL = 2 * time.Hour
+
+	require.NoError(t, sc.RefreshIfStale(context.Background()))
+	assert.Equal(t, 1, api.calls)
+
+	sc.TTL = time.Hour
+	api.sources = testCatalogSources()[:1]
+
+	require.NoError(t, sc.RefreshIfStale(context.Background()))
+	assert.Equal(t, 2, api.calls)
+	assert.Equal(t, now, sc.FetchedAt())
+	assert.Equal(t, testCatalogSources()[:1], sc.Sources())
+
+	_, ok := sc.ByID("cnn")
+	assert.False(t, ok)
+
+	api.errs = []error{assert.AnError, assert.AnError}
+
+	assert.Equal(t, assert.AnError, sc.Refresh(context.Background()))
+
+	_, err = LoadSourceCatalog(context.Background(), api, SourceParams{})
+	assert.Equal(t, assert.AnError, err)
+
+	assert.True(t, (&SourceCatalog{}).Stale())
+}
+
+func Test_SourceCatalog_ByID(t *testing.T) {
+	sc := NewSourceCatalog(testCatalogSources())
+
+	src, ok := sc.ByID("cnn")
+	assert.True(t, ok)
+	assert.Equal(t, "CNN", src.Name)
+
+	_, ok = sc.ByID("abc")
+	assert.False(t, ok)
+
+	_, ok = sc.ByID("")
+	assert.False(t, ok)
+}
+
+func Test_SourceCatalog_ByDomain(t *testing.T) {
+	sc := NewSourceCatalog(testCatalogSources())
+
+	tests := map[string]string{
+		"bbc.co.uk":                            "bbc-news",
+		"https://www.bbc.co.uk/sport/football": "bbc-news",
+		"edition.us.cnn.com":                   "cnn",
+		"https://US.CNN.com/2022/02/22/a.html": "cnn",
+		"www.wsj.com":                          "the-wall-street-journal",
+		"tagesspiegel.de:443":                  "der-tagesspiegel",
+	}
+
+	for domain, id := range tests {
+		src, ok := sc.ByDomain(domain)
+		if assert.True(t, ok, domain) {
+			assert.Equal(t, id, src.ID, domain)
+		}
+	}
+
+	for _, domain := range []string{"cnn.com", "example.com", "", "uk", "%"} {
+		_, ok := sc.ByDomain(domain)
+		assert.False(t, ok, domain)
+	}
+}
+
+func Test_SourceCatalog_Search(t *testing.T) {
+	sc := NewSourceCatalog(testCatalogSources())
+
+	tests := map[string][]string{
+		"bbc":                {"bbc-news", "bbc-sport"},
+		"BBC sport":          {"bbc-sport"},
+		"bbc-news":           {"bbc-news"},
+		"tagesspiegel":       {"der-tagesspiegel"},
+		"Wall Street Jurnal": {"the-wall-street-journal"},
+		"cnm":                {"cnn"},
+		"news":               {"bbc-news"},
+		"reuters":            nil,
+		"  ":                 nil,
+	}
+
+	for name, ids := range tests {
+		var res []string
+		for _, src := range sc.Search(name) {
+			res = append(res, src.ID)
+		}
+
+		assert.Equal(t, ids, res, name)
+	}
+}
+
+func Test_SourceCatalog_Filter(t *testing.T) {
+	sc := NewSourceCatalog(testCatalogSources())
+
+	var ids []string
+	for _, src := range sc.Filter(SourceParams{
+		Categories: []Category{CategoryGeneral, CategoryBusiness},
+		Countries:  []Country{CountryUnitedStates, CountryGermany},
+	}) {
+		ids = append(ids, src.ID)
+	}
+
+	assert.Equal(t, []string{"cnn", "der-tagesspiegel", "the-wall-street-journal"}, ids)
+	assert.Len(t, sc.Filter(SourceParams{}), 5)
+	assert.Empty(t, sc.Filter(SourceParams{Languages: []Language{LanguageFrench}}))
+}
+
+func Test_SourceCatalog_Enrich(t *testing.T) {
+	sc := NewSourceCatalog(testCatalogSources())
+	sources := testCatalogSources()
+
+	articles := []Article{
+		{Source: SourceID{ID: "cnn"}, URL: "https://www.bbc.co.uk/news/1"},
+		{Source: SourceID{Name: "BBC"}, URL: "https://www.bbc.co.uk/news/2"},
+		{Source: SourceID{Name: "the wall street journal"}, URL: "https://example.com"},
+		{Source: SourceID{Name: "Unknown"}, URL: "https://example.com"},
+	}
+
+	assert.Equal(t, []SourcedArticle{
+		{Article: articles[0], SourceDetails: &sources[2]},
+		{Article: articles[1], SourceDetails: &sources[0]},
+		{Article: articles[2], SourceDetails: &sources[4]},
+		{Article: articles[3]},
+	}, sc.Enrich(articles))
+}
+
+func Test_SourceCatalog_JSON(t *testing.T) {
+	now := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	sc := NewSourceCatalog(testCatalogSources())
+	sc.fetchedAt = now
+
+	data, err := json.Marshal(sc)
+	require.NoError(t, err)
+
+	var res SourceCatalog
+	require.NoError(t, json.Unmarshal(data, &res))
+	assert.Equal(t, now, res.FetchedAt())
+	assert.Equal(t, testCatalogSources(), res.Sources())
+
+	src, ok := res.ByDomain("wsj.com")
+	assert.True(t, ok)
+	assert.Equal(t, "the-wall-street-journal", src.ID)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"sources":{}}`), &res))
+}
+
+func Test_fuzzyDistance(t *testing.T) {
+	assert.Equal(t, 0, fuzzyDistance("", ""))
+	assert.Equal(t, 0, fuzzyDistance("", "abc"))
+	assert.Equal(t, 3, fuzzyDistance("abc", ""))
+	assert.Equal(t, 0, fuzzyDistance("street", "wall street journal"))
+	assert.Equal(t, 1, fuzzyDistance("cnn", "cnm"))
+	assert.Equal(t, 1, fuzzyDistance("jurnal", "wall street journal"))
+	assert.Equal(t, 2, fuzzyDistance("kitten", "sitting"))
+	assert.Equal(t, 1, fuzzyDistance("über", "uber"))
+}
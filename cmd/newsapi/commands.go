@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+// runEverything executes the everything command.
+func runEverything(ctx context.Context, args []string, env environment) error {
+	var (
+		cf             commonFlags
+		pr             newsapi.EverythingParams
+		searchIn       string
+		language       string
+		sortBy         string
+		sources        listFlag
+		domains        listFlag
+		excludeDomains listFlag
+		from, to       timeFlag
+		allPages       bool
+	)
+
+	fs := newFlagSet("everything", env)
+	cf.register(fs)
+	fs.StringVar(&pr.Query, "query", "", "keywords or phrases, advanced search is allowed")
+	fs.BoolVar(&pr.StrictQuery, "strict-query", false, "check query syntax before sending the request")
+	fs.StringVar(&pr.QueryInTitle, "query-in-title", "", "keywords or phrases to search for in the title")
+	fs.StringVar(&searchIn, "search-in", "", "article part to search in: title, description or content")
+	fs.Var(&sources, "sources", "comma separated source ids")
+	fs.Var(&domains, "domains", "comma separated domains to restrict the search to")
+	fs.Var(&excludeDomains, "exclude-domains", "comma separated domains to remove from the results")
+	fs.Var(&from, "from", "oldest allowed article date and time")
+	fs.Var(&to, "to", "newest allowed article date and time")
+	fs.StringVar(&language, "language", "", "article language")
+	fs.StringVar(&sortBy, "sort-by", "", "sort key: relevancy, popularity or publishedAt")
+	fs.UintVar(&pr.PageSize, "page-size", 0, "number of articles per page")
+	fs.UintVar(&pr.Page, "page", 0, "page number")
+	fs.BoolVar(&allPages, "all-pages", false, "retrieve all pages starting with the specified page")
+
+	if err := parseFlags(fs, args, env.stderr); err != nil {
+		return err
+	}
+
+	pr.SearchIn = newsapi.SearchIn(searchIn)
+	pr.Language = newsapi.Language(language)
+	pr.SortBy = newsapi.SortBy(sortBy)
+	pr.Sources = sources
+	pr.Domains = domains
+	pr.ExcludeDomains = excludeDomains
+	pr.From = from.Time
+	pr.To = to.Time
+
+	client, err := newClient(cf, env)
+	if err != nil {
+		return err
+	}
+
+	var articles []newsapi.Article
+
+	if allPages {
+		articles, err = collect(ctx, client.EverythingPager(pr))
+	} else {
+		articles, _, err = client.Everything(ctx, pr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printArticles(env.stdout, cf.format, articles)
+}
+
+// runHeadlines executes the headlines command.
+func runHeadlines(ctx context.Context, args []string, env environment) error {
+	var (
+		cf       commonFlags
+		pr       newsapi.TopHeadlinesParams
+		category string
+		language string
+		country  string
+		sources  listFlag
+		allPages bool
+	)
+
+	fs := newFlagSet("headlines", env)
+	cf.register(fs)
+	fs.StringVar(&pr.Query, "query", "", "keywords or phrases")
+	fs.StringVar(&category, "category", "", "article category")
+	fs.StringVar(&language, "language", "", "article language")
+	fs.StringVar(&country, "country", "", "article country")
+	fs.Var(&sources, "sources", "comma separated source ids")
+	fs.UintVar(&pr.PageSize, "page-size", 0, "number of articles per page")
+	fs.UintVar(&pr.Page, "page", 0, "page number")
+	fs.BoolVar(&allPages, "all-pages", false, "retrieve all pages starting with the specified page")
+
+	if err := parseFlags(fs, args, env.stderr); err != nil {
+		return err
+	}
+
+	pr.Category = newsapi.Category(category)
+	pr.Language = newsapi.Language(language)
+	pr.Country = newsapi.Country(country)
+	pr.Sources = sources
+
+	client, err := newClient(cf, env)
+	if err != nil {
+		return err
+	}
+
+	var articles []newsapi.Article
+
+	if allPages {
+		articles, err = collect(ctx, client.TopHeadlinesPager(pr))
+	} else {
+		articles, _, err = client.TopHeadlines(ctx, pr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printArticles(env.stdout, cf.format, articles)
+}
+
+// runSources executes the sources command.
+func runSources(ctx context.Context, args []string, env environment) error {
+	var (
+		cf         commonFlags
+		pr         newsapi.SourceParams
+		categories listFlag
+		languages  listFlag
+		countries  listFlag
+	)
+
+	fs := newFlagSet("sources", env)
+	cf.register(fs)
+	fs.Var(&categories, "categories", "comma separated source categories")
+	fs.Var(&languages, "languages", "comma separated source languages")
+	fs.Var(&countries, "countries", "comma separated source countries")
+
+	if err := parseFlags(fs, args, env.stderr); err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		pr.Categories = append(pr.Categories, newsapi.Category(category))
+	}
+
+	for _, language := range languages {
+		pr.Languages = append(pr.Languages, newsapi.Language(language))
+	}
+
+	for _, country := range countries {
+		pr.Countries = append(pr.Countries, newsapi.Country(country))
+	}
+
+	client, err := newClient(cf, env)
+	if err != nil {
+		return err
+	}
+
+	sources, err := client.Sources(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	return printSources(env.stdout, cf.format, sources)
+}
+
+// newFlagSet creates a flag set of the command.
+func newFlagSet(name string, env environment) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: newsapi %s [flags]\n\nFlags:\n", name)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// newClient creates a newsapi client from the common flags, the
+// environment and the config file.
+func newClient(cf commonFlags, env environment) (*newsapi.Client, error) {
+	if !isFormat(cf.format) {
+		return nil, &usageError{err: fmt.Errorf("invalid output format %q", cf.format)}
+	}
+
+	path := cf.configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+
+	cfg, err := loadConfig(path, cf.configPath != "")
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := env.getenv(_envAPIKey)
+	if apiKey == "" {
+		apiKey = cfg.APIKey
+	}
+
+	if apiKey == "" {
+		return nil, errors.New("api key is not set, use " + _envAPIKey + " environment variable or the config file")
+	}
+
+	opts := []newsapi.ClientOption{
+		newsapi.WithHTTPClient(&http.Client{
+			Timeout: cf.timeout,
+		}),
+	}
+
+	baseURL := cf.baseURL
+	if baseURL == "" {
+		baseURL = cfg.BaseURL
+	}
+
+	if baseURL != "" {
+		opts = append(opts, newsapi.WithBaseURL(withTrailingSlash(baseURL)))
+	}
+
+	return newsapi.NewClient(apiKey, opts...), nil
+}
+
+// collect retrieves all articles of the pager.
+func collect(ctx context.Context, pager *newsapi.ArticlePager) ([]newsapi.Article, error) {
+	var articles []newsapi.Article
+
+	for pager.Next(ctx) {
+		articles = append(articles, pager.Article())
+	}
+
+	return articles, pager.Err()
+}
+
+// withTrailingSlash appends a slash to the url if it is missing, since
+// endpoint paths are relative to the base url.
+func withTrailingSlash(url string) string {
+	if url[len(url)-1] == '/' {
+		return url
+	}
+
+	return url + "/"
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// _envAPIKey is the environment variable holding the api key.
+const _envAPIKey = "NEWSAPI_KEY"
+
+// config contains settings read from the config file.
+type config struct {
+	// APIKey specifies the newsapi api key.
+	APIKey string `json:"apiKey"`
+
+	// BaseURL specifies the newsapi base url.
+	BaseURL string `json:"baseURL"`
+}
+
+// defaultConfigPath returns the path of the config file that is used
+// when it is not specified explicitly.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "newsapi", "config.json")
+}
+
+// loadConfig reads the config file. A missing file is not an error
+// unless the path was specified explicitly.
+func loadConfig(path string, explicit bool) (config, error) {
+	var cfg config
+
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return cfg, err
+	}
+
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadConfig(t *testing.T) {
+	cfg, err := loadConfig("testdata/config.json", true)
+	assert.NoError(t, err)
+	assert.Equal(t, config{APIKey: "777"}, cfg)
+
+	cfg, err = loadConfig("testdata/missing.json", false)
+	assert.NoError(t, err)
+	assert.Equal(t, config{}, cfg)
+
+	_, err = loadConfig("testdata/missing.json", true)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err = loadConfig(path, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// _timeLayouts are the accepted layouts of time flags.
+var _timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// commonFlags contains flags shared by all commands.
+type commonFlags struct {
+	format     string
+	baseURL    string
+	configPath string
+	timeout    time.Duration
+}
+
+// register registers common flags in the flag set.
+func (cf *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.format, "format", formatTable, "output format: table, json, jsonl or csv")
+	fs.StringVar(&cf.baseURL, "base-url", "", "newsapi base url")
+	fs.StringVar(&cf.configPath, "config", "", "config file path (default "+defaultConfigPath()+")")
+	fs.DurationVar(&cf.timeout, "timeout", 10*time.Second, "timeout of a single request")
+}
+
+// parseFlags parses the arguments and checks that no positional
+// arguments remain. Usage is written to w only when help is requested.
+func parseFlags(fs *flag.FlagSet, args []string, w io.Writer) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			fs.SetOutput(w)
+			fs.Usage()
+
+			return err
+		}
+
+		return &usageError{err: err}
+	}
+
+	if fs.NArg() != 0 {
+		return &usageError{err: fmt.Errorf("unexpected argument %q", fs.Arg(0))}
+	}
+
+	return nil
+}
+
+// listFlag is a flag that accepts comma separated values and can be
+// repeated.
+type listFlag []string
+
+// String returns the values joined with commas.
+func (lf *listFlag) String() string {
+	return strings.Join(*lf, ",")
+}
+
+// Set appends comma separated values to the list.
+func (lf *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*lf = append(*lf, v)
+		}
+	}
+
+	return nil
+}
+
+// timeFlag is a flag that accepts a date or a date and time.
+type timeFlag struct {
+	time.Time
+}
+
+// String returns the time in RFC 3339 format.
+func (tf *timeFlag) String() string {
+	if tf.IsZero() {
+		return ""
+	}
+
+	return tf.Format(time.RFC3339)
+}
+
+// Set parses the value using one of the accepted layouts.
+func (tf *timeFlag) Set(value string) error {
+	for _, layout := range _timeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			tf.Time = t
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid time %q, expected RFC 3339 time or YYYY-MM-DD date", value)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFlags(t *testing.T) {
+	var (
+		out bytes.Buffer
+		v   string
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&v, "value", "", "test value")
+
+	assert.NoError(t, parseFlags(fs, []string{"-value", "1"}, &out))
+	assert.Equal(t, "1", v)
+	assert.Empty(t, out.String())
+
+	assert.Equal(t, flag.ErrHelp, parseFlags(fs, []string{"-h"}, &out))
+	assert.Contains(t, out.String(), "test value")
+
+	assert.IsType(t, &usageError{}, parseFlags(fs, []string{"-test"}, &out))
+	assert.IsType(t, &usageError{}, parseFlags(fs, []string{"-value", "1", "2"}, &out))
+}
+
+func Test_listFlag_Set(t *testing.T) {
+	var lf listFlag
+
+	assert.NoError(t, lf.Set("bbc, wired,"))
+	assert.NoError(t, lf.Set("spiegel"))
+	assert.Equal(t, listFlag{"bbc", "wired", "spiegel"}, lf)
+	assert.Equal(t, "bbc,wired,spiegel", lf.String())
+}
+
+func Test_timeFlag_Set(t *testing.T) {
+	tests := map[string]struct {
+		Value string
+		Time  time.Time
+		Err   bool
+	}{
+		"RFC 3339": {
+			Value: "2022-02-22T22:22:22+02:00",
+			Time:  time.Date(2022, 02, 22, 20, 22, 22, 0, time.UTC),
+		},
+		"Date and time": {
+			Value: "2022-02-22T22:22:22",
+			Time:  time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC),
+		},
+		"Date": {
+			Value: "2022-02-22",
+			Time:  time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC),
+		},
+		"Invalid": {
+			Value: "22/02/2022",
+			Err:   true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var tf timeFlag
+
+			err := tf.Set(test.Value)
+			if test.Err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, test.Time.Equal(tf.Time))
+		})
+	}
+}
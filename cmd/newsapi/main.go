@@ -0,0 +1,100 @@
+// Command newsapi queries newsapi endpoints from the command line.
+//
+// Usage:
+//
+//	newsapi <command> [flags]
+//
+// Available commands are everything, headlines and sources. The api key is
+// read from NEWSAPI_KEY environment variable or, if it is not set, from
+// the config file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const _usage = `Usage: newsapi <command> [flags]
+
+Commands:
+  everything  search through all articles
+  headlines   retrieve top headlines
+  sources     retrieve available sources
+
+Run 'newsapi <command> -h' to list command flags.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Getenv, os.Stdout, os.Stderr)
+	stop()
+
+	os.Exit(code)
+}
+
+// run executes the command specified by the arguments and returns the
+// exit code.
+func run(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, _usage)
+		return 2
+	}
+
+	var cmd func(ctx context.Context, args []string, env environment) error
+
+	switch args[0] {
+	case "everything":
+		cmd = runEverything
+	case "headlines":
+		cmd = runHeadlines
+	case "sources":
+		cmd = runSources
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, _usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "newsapi: unknown command %q\n\n%s", args[0], _usage)
+		return 2
+	}
+
+	err := cmd(ctx, args[1:], environment{
+		getenv: getenv,
+		stdout: stdout,
+		stderr: stderr,
+	})
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, new(*usageError)):
+		fmt.Fprintf(stderr, "newsapi %s: %v\nRun 'newsapi %s -h' for usage.\n", args[0], err, args[0])
+		return 2
+	default:
+		fmt.Fprintf(stderr, "newsapi %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+// environment contains the process environment of a command.
+type environment struct {
+	getenv func(string) string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// usageError is returned whenever command flags are invalid.
+type usageError struct {
+	err error
+}
+
+// Error returns the underlying error message.
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+	"github.com/jellydator/newsapi-go/newsapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer() *newsapitest.Server {
+	tstamp := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	return newsapitest.NewServer(
+		newsapitest.WithAPIKey("777"),
+		newsapitest.WithSources(
+			newsapi.Source{
+				SourceID: newsapi.SourceID{ID: "bbc", Name: "BBC"},
+				URL:      "https://www.bbc.co.uk",
+				Category: newsapi.CategoryGeneral,
+				Language: newsapi.LanguageEnglish,
+				Country:  newsapi.CountryUnitedKingdom,
+			},
+			newsapi.Source{
+				SourceID: newsapi.SourceID{ID: "wired", Name: "Wired"},
+				URL:      "https://www.wired.com",
+				Category: newsapi.CategoryTechnology,
+				Language: newsapi.LanguageEnglish,
+				Country:  newsapi.CountryUnitedStates,
+			},
+		),
+		newsapitest.WithArticles(
+			newsapi.Article{
+				Source:      newsapi.SourceID{ID: "bbc", Name: "BBC"},
+				Title:       "Bitcoin price falls",
+				URL:         "https://www.bbc.co.uk/news/1",
+				PublishedAt: tstamp,
+			},
+			newsapi.Article{
+				Source:      newsapi.SourceID{ID: "wired", Name: "Wired"},
+				Title:       "Bitcoin wallet released",
+				URL:         "https://wired.com/1",
+				PublishedAt: tstamp.Add(time.Hour),
+			},
+		),
+	)
+}
+
+func testEnv(key string) func(string) string {
+	return func(name string) string {
+		if name == _envAPIKey {
+			return key
+		}
+
+		return ""
+	}
+}
+
+func Test_run(t *testing.T) {
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	tests := map[string]struct {
+		Args   []string
+		Key    string
+		Code   int
+		Stdout string
+		Stderr string
+	}{
+		"No command": {
+			Code:   2,
+			Stderr: _usage,
+		},
+		"Help": {
+			Args:   []string{"help"},
+			Stdout: _usage,
+		},
+		"Unknown command": {
+			Args:   []string{"test"},
+			Code:   2,
+			Stderr: "newsapi: unknown command \"test\"\n\n" + _usage,
+		},
+		"Invalid flag value": {
+			Args: []string{"everything", "-from", "yesterday"},
+			Key:  "777",
+			Code: 2,
+			Stderr: "newsapi everything: invalid value \"yesterday\" for flag -from: invalid time \"yesterday\", expected RFC 3339 time or YYYY-MM-DD date\n" +
+				"Run 'newsapi everything -h' for usage.\n",
+		},
+		"Unexpected argument": {
+			Args:   []string{"sources", "bbc"},
+			Key:    "777",
+			Code:   2,
+			Stderr: "newsapi sources: unexpected argument \"bbc\"\nRun 'newsapi sources -h' for usage.\n",
+		},
+		"Invalid format": {
+			Args:   []string{"sources", "-format", "xml"},
+			Key:    "777",
+			Code:   2,
+			Stderr: "newsapi sources: invalid output format \"xml\"\nRun 'newsapi sources -h' for usage.\n",
+		},
+		"Missing config file": {
+			Args:   []string{"sources", "-config", "testdata/missing.json"},
+			Code:   1,
+			Stderr: "newsapi sources: open testdata/missing.json: no such file or directory\n",
+		},
+		"Missing api key": {
+			Args:   []string{"sources", "-config", "testdata/empty.json"},
+			Code:   1,
+			Stderr: "newsapi sources: api key is not set, use NEWSAPI_KEY environment variable or the config file\n",
+		},
+		"Api key from config file": {
+			Args:   []string{"sources", "-config", "testdata/config.json", "-base-url", srv.URL, "-countries", "gb", "-format", "csv"},
+			Stdout: "id,name,description,url,category,language,country\nbbc,BBC,,https://www.bbc.co.uk,general,en,gb\n",
+		},
+		"Invalid params": {
+			Args:   []string{"everything", "-base-url", srv.URL, "-query", "bitcoin", "-language", "xx"},
+			Key:    "777",
+			Code:   1,
+			Stderr: "newsapi everything: invalid parameters: Language: invalid language\n",
+		},
+		"Invalid api key": {
+			Args:   []string{"sources", "-base-url", srv.URL},
+			Key:    "123",
+			Code:   1,
+			Stderr: "newsapi sources: message: \"Your API key is invalid or incorrect.\" (http code: \"401\"; api code: \"apiKeyInvalid\")\n",
+		},
+		"Everything": {
+			Args: []string{"everything", "-base-url", srv.URL, "-query", "bitcoin", "-sources", "bbc,wired", "-format", "csv"},
+			Key:  "777",
+			Stdout: "publishedAt,sourceId,sourceName,author,title,description,url,urlToImage,content\n" +
+				"2022-02-22T23:22:22Z,wired,Wired,,Bitcoin wallet released,,https://wired.com/1,,\n" +
+				"2022-02-22T22:22:22Z,bbc,BBC,,Bitcoin price falls,,https://www.bbc.co.uk/news/1,,\n",
+		},
+		"Everything with all pages": {
+			Args: []string{"everything", "-base-url", srv.URL + "/", "-query", "bitcoin", "-page-size", "1", "-all-pages", "-format", "table"},
+			Key:  "777",
+			Stdout: "PUBLISHED             SOURCE  TITLE                    URL\n" +
+				"2022-02-22T23:22:22Z  Wired   Bitcoin wallet released  https://wired.com/1\n" +
+				"2022-02-22T22:22:22Z  BBC     Bitcoin price falls      https://www.bbc.co.uk/news/1\n",
+		},
+		"Headlines": {
+			Args: []string{"headlines", "-base-url", srv.URL, "-country", "gb", "-format", "jsonl"},
+			Key:  "777",
+			Stdout: `{"source":{"id":"bbc","name":"BBC"},"author":"","title":"Bitcoin price falls","description":"",` +
+				`"url":"https://www.bbc.co.uk/news/1","urlToImage":"","publishedAt":"2022-02-22T22:22:22Z","content":""}` + "\n",
+		},
+		"Sources": {
+			Args: []string{"sources", "-base-url", srv.URL, "-categories", "technology"},
+			Key:  "777",
+			Stdout: "ID     NAME   CATEGORY    LANGUAGE  COUNTRY  URL\n" +
+				"wired  Wired  technology  en        us       https://www.wired.com\n",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			code := run(context.Background(), test.Args, testEnv(test.Key), &stdout, &stderr)
+			assert.Equal(t, test.Code, code)
+			assert.Equal(t, test.Stdout, stdout.String())
+			assert.Equal(t, test.Stderr, stderr.String())
+		})
+	}
+}
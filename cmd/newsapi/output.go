@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+// All available output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// isFormat checks if the output format is valid.
+func isFormat(format string) bool {
+	switch format {
+	case formatTable,
+		formatJSON,
+		formatJSONL,
+		formatCSV:
+
+		return true
+	}
+
+	return false
+}
+
+// printArticles writes articles in the specified format.
+func printArticles(w io.Writer, format string, articles []newsapi.Article) error {
+	switch format {
+	case formatJSON:
+		return printJSON(w, articles)
+	case formatJSONL:
+		return printJSONL(w, len(articles), func(i int) interface{} {
+			return articles[i]
+		})
+	case formatCSV:
+		rows := make([][]string, 0, len(articles)+1)
+		rows = append(rows, []string{
+			"publishedAt", "sourceId", "sourceName", "author",
+			"title", "description", "url", "urlToImage", "content",
+		})
+
+		for _, a := range articles {
+			rows = append(rows, []string{
+				formatTime(a.PublishedAt), a.Source.ID, a.Source.Name, a.Author,
+				a.Title, a.Description, a.URL, a.URLToImage, a.Content,
+			})
+		}
+
+		return printCSV(w, rows)
+	}
+
+	rows := make([][]string, 0, len(articles)+1)
+	rows = append(rows, []string{"PUBLISHED", "SOURCE", "TITLE", "URL"})
+
+	for _, a := range articles {
+		rows = append(rows, []string{
+			formatTime(a.PublishedAt), a.Source.Name, a.Title, a.URL,
+		})
+	}
+
+	return printTable(w, rows)
+}
+
+// printSources writes sources in the specified format.
+func printSources(w io.Writer, format string, sources []newsapi.Source) error {
+	switch format {
+	case formatJSON:
+		return printJSON(w, sources)
+	case formatJSONL:
+		return printJSONL(w, len(sources), func(i int) interface{} {
+			return sources[i]
+		})
+	case formatCSV:
+		rows := make([][]string, 0, len(sources)+1)
+		rows = append(rows, []string{
+			"id", "name", "description", "url", "category", "language", "country",
+		})
+
+		for _, s := range sources {
+			rows = append(rows, []string{
+				s.ID, s.Name, s.Description, s.URL,
+				string(s.Category), string(s.Language), string(s.Country),
+			})
+		}
+
+		return printCSV(w, rows)
+	}
+
+	rows := make([][]string, 0, len(sources)+1)
+	rows = append(rows, []string{"ID", "NAME", "CATEGORY", "LANGUAGE", "COUNTRY", "URL"})
+
+	for _, s := range sources {
+		rows = append(rows, []string{
+			s.ID, s.Name, string(s.Category), string(s.Language), string(s.Country), s.URL,
+		})
+	}
+
+	return printTable(w, rows)
+}
+
+// printJSON writes the value as an indented JSON document.
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// printJSONL writes n values, one JSON document per line.
+func printJSONL(w io.Writer, n int, value func(i int) interface{}) error {
+	enc := json.NewEncoder(w)
+
+	for i := 0; i < n; i++ {
+		if err := enc.Encode(value(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printCSV writes the rows as CSV records.
+func printCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// printTable writes the rows as tab aligned columns. Line breaks and
+// tabs inside cells are replaced with spaces.
+func printTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	replacer := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = replacer.Replace(cell)
+		}
+
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// formatTime formats the time in RFC 3339 format, leaving zero time
+// empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_printArticles(t *testing.T) {
+	articles := []newsapi.Article{{
+		Source:      newsapi.SourceID{ID: "bbc", Name: "BBC"},
+		Title:       "Bitcoin\nprice, falls",
+		URL:         "https://www.bbc.co.uk/news/1",
+		PublishedAt: time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC),
+	}}
+
+	tests := map[string]string{
+		formatTable: "PUBLISHED             SOURCE  TITLE                 URL\n" +
+			"2022-02-22T22:22:22Z  BBC     Bitcoin price, falls  https://www.bbc.co.uk/news/1\n",
+		formatCSV: "publishedAt,sourceId,sourceName,author,title,description,url,urlToImage,content\n" +
+			"2022-02-22T22:22:22Z,bbc,BBC,,\"Bitcoin\nprice, falls\",,https://www.bbc.co.uk/news/1,,\n",
+		formatJSON: "[\n  {\n    \"source\": {\n      \"id\": \"bbc\",\n      \"name\": \"BBC\"\n    },\n" +
+			"    \"author\": \"\",\n    \"title\": \"Bitcoin\\nprice, falls\",\n    \"description\": \"\",\n" +
+			"    \"url\": \"https://www.bbc.co.uk/news/1\",\n    \"urlToImage\": \"\",\n" +
+			"    \"publishedAt\": \"2022-02-22T22:22:22Z\",\n    \"content\": \"\"\n  }\n]\n",
+	}
+
+	for format, output := range tests {
+		format, output := format, output
+
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			assert.NoError(t, printArticles(&buf, format, articles))
+			assert.Equal(t, output, buf.String())
+		})
+	}
+}
+
+func Test_printSources(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, printSources(&buf, formatJSONL, []newsapi.Source{
+		{SourceID: newsapi.SourceID{ID: "bbc", Name: "BBC"}},
+		{SourceID: newsapi.SourceID{ID: "wired", Name: "Wired"}},
+	}))
+	assert.Equal(
+		t,
+		`{"id":"bbc","name":"BBC","description":"","url":"","category":"","language":"","country":""}`+"\n"+
+			`{"id":"wired","name":"Wired","description":"","url":"","category":"","language":"","country":""}`+"\n",
+		buf.String(),
+	)
+
+	buf.Reset()
+
+	assert.NoError(t, printSources(&buf, formatJSON, nil))
+	assert.Equal(t, "null\n", buf.String())
+}
+
+func Test_isFormat(t *testing.T) {
+	assert.True(t, isFormat(formatTable))
+	assert.True(t, isFormat(formatJSONL))
+	assert.False(t, isFormat("xml"))
+}
@@ -3,6 +3,25 @@ package newsapi
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// All documented newsapi error codes.
+const (
+	APICodeAPIKeyDisabled        APICode = "apiKeyDisabled"
+	APICodeAPIKeyExhausted       APICode = "apiKeyExhausted"
+	APICodeAPIKeyInvalid         APICode = "apiKeyInvalid"
+	APICodeAPIKeyMissing         APICode = "apiKeyMissing"
+	APICodeParameterInvalid      APICode = "parameterInvalid"
+	APICodeParametersMissing     APICode = "parametersMissing"
+	APICodeRateLimited           APICode = "rateLimited"
+	APICodeSourcesTooMany        APICode = "sourcesTooMany"
+	APICodeSourceDoesNotExist    APICode = "sourceDoesNotExist"
+	APICodeMaximumResultsReached APICode = "maximumResultsReached"
+	APICodeUnexpectedError       APICode = "unexpectedError"
 )
 
 var (
@@ -49,18 +68,127 @@ var (
 	// ErrParamsScopeTooBroad is returned when the scope of parameters is
 	// too broad.
 	ErrParamsScopeTooBroad = errors.New("scope of parameters is too broad")
+
+	// ErrInvalidHarvestWindow is returned whenever harvested parameters
+	// do not specify both from and to times.
+	ErrInvalidHarvestWindow = errors.New("harvest requires from and to times")
+
+	// ErrCheckpointMismatch is returned whenever a harvest job is resumed
+	// from a checkpoint with different parameters.
+	ErrCheckpointMismatch = errors.New("checkpoint parameters do not match job parameters")
+
+	// ErrInvalidWatchParams is returned whenever a watcher has either
+	// both or none of its endpoint parameters set.
+	ErrInvalidWatchParams = errors.New("exactly one of everything or top headlines params must be set")
+
+	// ErrInvalidJobParams is returned whenever a scheduled job has
+	// either both or none of its endpoint parameters set.
+	ErrInvalidJobParams = errors.New("exactly one of everything or top headlines job params must be set")
+
+	// ErrDuplicateJob is returned whenever a job with the same id is
+	// already scheduled.
+	ErrDuplicateJob = errors.New("job is already scheduled")
+
+	// ErrWatchlistQuery is returned whenever watchlist parameters
+	// specify a query, which would be replaced by the packed queries.
+	ErrWatchlistQuery = errors.New("watchlist params must not specify a query")
+
+	// ErrNoLanguages is returned whenever multi-language parameters do
+	// not specify any languages.
+	ErrNoLanguages = errors.New("at least one language must be specified")
+
+	// ErrBudgetExhausted is returned whenever the daily budget of
+	// requests has been spent.
+	ErrBudgetExhausted = errors.New("daily request budget exhausted")
 )
 
+var (
+	// ErrAPIKeyDisabled matches newsapi errors with apiKeyDisabled code.
+	ErrAPIKeyDisabled = errors.New("api key has been disabled")
+
+	// ErrAPIKeyExhausted matches newsapi errors with apiKeyExhausted
+	// code.
+	ErrAPIKeyExhausted = errors.New("api key has no more requests available")
+
+	// ErrAPIKeyInvalid matches newsapi errors with apiKeyInvalid code.
+	ErrAPIKeyInvalid = errors.New("api key is invalid")
+
+	// ErrAPIKeyMissing matches newsapi errors with apiKeyMissing code.
+	ErrAPIKeyMissing = errors.New("api key is missing")
+
+	// ErrParameterInvalid matches newsapi errors with parameterInvalid
+	// code.
+	ErrParameterInvalid = errors.New("parameter is not supported")
+
+	// ErrParametersMissing matches newsapi errors with parametersMissing
+	// code.
+	ErrParametersMissing = errors.New("required parameters are missing")
+
+	// ErrRateLimited matches newsapi errors with rateLimited code.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrSourcesTooMany matches newsapi errors with sourcesTooMany code.
+	// Unlike ErrTooManySources, it is returned by newsapi rather than
+	// by parameters validation.
+	ErrSourcesTooMany = errors.New("too many sources requested")
+
+	// ErrSourceDoesNotExist matches newsapi errors with
+	// sourceDoesNotExist code.
+	ErrSourceDoesNotExist = errors.New("source does not exist")
+
+	// ErrMaximumResultsReached matches newsapi errors with
+	// maximumResultsReached code.
+	ErrMaximumResultsReached = errors.New("maximum number of results reached")
+
+	// ErrUnexpectedError matches newsapi errors with unexpectedError
+	// code.
+	ErrUnexpectedError = errors.New("unexpected newsapi error")
+)
+
+// _apiCodeErrors maps newsapi error codes to errors they match.
+var _apiCodeErrors = map[APICode]error{
+	APICodeAPIKeyDisabled:        ErrAPIKeyDisabled,
+	APICodeAPIKeyExhausted:       ErrAPIKeyExhausted,
+	APICodeAPIKeyInvalid:         ErrAPIKeyInvalid,
+	APICodeAPIKeyMissing:         ErrAPIKeyMissing,
+	APICodeParameterInvalid:      ErrParameterInvalid,
+	APICodeParametersMissing:     ErrParametersMissing,
+	APICodeRateLimited:           ErrRateLimited,
+	APICodeSourcesTooMany:        ErrSourcesTooMany,
+	APICodeSourceDoesNotExist:    ErrSourceDoesNotExist,
+	APICodeMaximumResultsReached: ErrMaximumResultsReached,
+	APICodeUnexpectedError:       ErrUnexpectedError,
+}
+
+// APICode determines the error code returned from newsapi.
+type APICode string
+
 // Error contains newsapi error information.
 type Error struct {
 	// HTTPCode specifies the response status code.
 	HTTPCode int
 
 	// APICode specifies the error code returned from newsapi.
-	APICode string
+	APICode APICode
 
 	// Message specifies the error message returned from newsapi.
 	Message string
+
+	// RetryAfter specifies how long to wait before sending another
+	// request, as advised by the response headers. It is zero when
+	// the headers are not present.
+	RetryAfter time.Duration
+}
+
+// newError creates a fresh instance of newsapi error from the response
+// and the decoded error payload.
+func newError(resp *http.Response, code APICode, message string) *Error {
+	return &Error{
+		HTTPCode:   resp.StatusCode,
+		APICode:    code,
+		Message:    message,
+		RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+	}
 }
 
 // Error implements error interface and returns formatted error message.
@@ -72,3 +200,153 @@ func (e *Error) Error() string {
 		e.APICode,
 	)
 }
+
+// Is reports whether the target is the error matching the newsapi error
+// code, e.g. ErrRateLimited.
+func (e *Error) Is(target error) bool {
+	err, ok := _apiCodeErrors[e.APICode]
+	return ok && err == target
+}
+
+// FieldError contains a validation failure of a single parameter.
+type FieldError struct {
+	// Field specifies the name of the failing params struct field. It is
+	// empty when the failure concerns the parameters as a whole, e.g.
+	// when their scope is too broad.
+	Field string
+
+	// Value specifies the offending value. It is nil when the failure
+	// concerns the parameters as a whole.
+	Value interface{}
+
+	// Err specifies the underlying error, e.g. ErrInvalidLanguage.
+	Err error
+}
+
+// Error implements error interface and returns formatted error message.
+func (fe FieldError) Error() string {
+	if fe.Field == "" {
+		return fe.Err.Error()
+	}
+
+	return fe.Field + ": " + fe.Err.Error()
+}
+
+// ValidationError contains all validation failures of parameters.
+type ValidationError struct {
+	// Fields specifies validation failures in the order of params
+	// struct fields.
+	Fields []FieldError
+}
+
+// add appends a validation failure of the field.
+func (ve *ValidationError) add(field string, value interface{}, err error) {
+	ve.Fields = append(ve.Fields, FieldError{
+		Field: field,
+		Value: value,
+		Err:   err,
+	})
+}
+
+// err returns the validation error if any failures were added, nil
+// otherwise.
+func (ve *ValidationError) err() error {
+	if len(ve.Fields) == 0 {
+		return nil
+	}
+
+	return ve
+}
+
+// Error implements error interface and returns formatted error message.
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Fields))
+	for _, fe := range ve.Fields {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return "invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the field errors matches the target, e.g.
+// ErrInvalidLanguage.
+func (ve *ValidationError) Is(target error) bool {
+	for _, fe := range ve.Fields {
+		if errors.Is(fe.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first field error that matches the target, e.g.
+// *query.SyntaxError, and sets the target to it.
+func (ve *ValidationError) As(target interface{}) bool {
+	for _, fe := range ve.Fields {
+		if errors.As(fe.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRetryable reports whether the error is a newsapi error after which
+// the request can be retried.
+func IsRetryable(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return DefaultRetryClassifier(apiErr.HTTPCode, apiErr, nil)
+}
+
+// IsAuthError reports whether the error is caused by a missing, invalid
+// or disabled api key.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAPIKeyMissing) ||
+		errors.Is(err, ErrAPIKeyInvalid) ||
+		errors.Is(err, ErrAPIKeyDisabled)
+}
+
+// IsQuotaError reports whether the error is caused by an exhausted
+// request quota, either reported by newsapi or enforced by the daily
+// budget of the client.
+func IsQuotaError(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrAPIKeyExhausted) ||
+		errors.Is(err, ErrBudgetExhausted)
+}
+
+// parseRetryAfter parses the delay advised by Retry-After header or, if
+// it is not present, by X-RateLimit-Reset header containing a unix
+// timestamp.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if secs < 0 {
+				return 0
+			}
+
+			return time.Duration(secs) * time.Second
+		}
+
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+
+		return 0
+	}
+
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if t := time.Unix(secs, 0); t.After(now) {
+				return t.Sub(now)
+			}
+		}
+	}
+
+	return 0
+}
@@ -1,7 +1,11 @@
 package newsapi
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,3 +19,177 @@ func Test_Error_Error(t *testing.T) {
 
 	assert.EqualError(t, err, `message: "321" (http code: "500"; api code: "123")`)
 }
+
+func Test_Error_Is(t *testing.T) {
+	for code, target := range _apiCodeErrors {
+		err := fmt.Errorf("wrapped: %w", &Error{APICode: code})
+
+		assert.ErrorIs(t, err, target)
+		assert.NotErrorIs(t, err, ErrInvalidCategory)
+	}
+
+	assert.NotErrorIs(t, &Error{APICode: "123"}, ErrRateLimited)
+	assert.NotErrorIs(t, &Error{APICode: APICodeRateLimited}, ErrAPIKeyInvalid)
+}
+
+func Test_FieldError_Error(t *testing.T) {
+	assert.EqualError(t, FieldError{Field: "Language", Err: ErrInvalidLanguage}, "Language: invalid language")
+	assert.EqualError(t, FieldError{Err: ErrParamsScopeTooBroad}, "scope of parameters is too broad")
+}
+
+func Test_ValidationError_Error(t *testing.T) {
+	err := &ValidationError{Fields: []FieldError{
+		{Field: "Language", Value: Language("123"), Err: ErrInvalidLanguage},
+		{Err: ErrParamsScopeTooBroad},
+	}}
+
+	assert.EqualError(t, err, "invalid parameters: Language: invalid language; scope of parameters is too broad")
+}
+
+func Test_ValidationError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ValidationError{Fields: []FieldError{
+		{Field: "Language", Err: ErrInvalidLanguage},
+		{Field: "Country", Err: fmt.Errorf("wrapped: %w", ErrInvalidCountry)},
+	}})
+
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+	assert.ErrorIs(t, err, ErrInvalidCountry)
+	assert.NotErrorIs(t, err, ErrInvalidCategory)
+}
+
+func Test_ValidationError_As(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ValidationError{Fields: []FieldError{
+		{Field: "Language", Err: ErrInvalidLanguage},
+		{Field: "Query", Err: &Error{APICode: "123"}},
+	}})
+
+	var apiErr *Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, APICode("123"), apiErr.APICode)
+	}
+
+	var ve *ValidationError
+	assert.ErrorAs(t, err, &ve)
+
+	assert.False(t, (&ValidationError{}).As(&apiErr))
+}
+
+func Test_ValidationError_err(t *testing.T) {
+	var ve ValidationError
+	assert.NoError(t, ve.err())
+
+	ve.add("Language", Language("123"), ErrInvalidLanguage)
+	assert.Equal(t, &ve, ve.err())
+}
+
+func Test_IsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&Error{
+		HTTPCode: http.StatusTooManyRequests,
+		APICode:  APICodeRateLimited,
+	}))
+	assert.True(t, IsRetryable(&Error{
+		HTTPCode: http.StatusInternalServerError,
+		APICode:  APICodeUnexpectedError,
+	}))
+	assert.True(t, IsRetryable(fmt.Errorf("wrapped: %w", &Error{
+		HTTPCode: http.StatusBadGateway,
+	})))
+	assert.False(t, IsRetryable(&Error{
+		HTTPCode: http.StatusUnauthorized,
+		APICode:  APICodeAPIKeyInvalid,
+	}))
+	assert.False(t, IsRetryable(assert.AnError))
+}
+
+func Test_IsAuthError(t *testing.T) {
+	assert.True(t, IsAuthError(&Error{APICode: APICodeAPIKeyMissing}))
+	assert.True(t, IsAuthError(&Error{APICode: APICodeAPIKeyInvalid}))
+	assert.True(t, IsAuthError(&Error{APICode: APICodeAPIKeyDisabled}))
+	assert.False(t, IsAuthError(&Error{APICode: APICodeRateLimited}))
+	assert.False(t, IsAuthError(assert.AnError))
+}
+
+func Test_IsQuotaError(t *testing.T) {
+	assert.True(t, IsQuotaError(&Error{APICode: APICodeRateLimited}))
+	assert.True(t, IsQuotaError(&Error{APICode: APICodeAPIKeyExhausted}))
+	assert.True(t, IsQuotaError(fmt.Errorf("wrapped: %w", ErrBudgetExhausted)))
+	assert.False(t, IsQuotaError(&Error{APICode: APICodeAPIKeyInvalid}))
+	assert.False(t, IsQuotaError(assert.AnError))
+}
+
+func Test_newError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"Retry-After": []string{"120"},
+		},
+	}
+
+	assert.Equal(t, &Error{
+		HTTPCode:   http.StatusTooManyRequests,
+		APICode:    APICodeRateLimited,
+		Message:    "slow down",
+		RetryAfter: 2 * time.Minute,
+	}, newError(resp, APICodeRateLimited, "slow down"))
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	tests := map[string]struct {
+		Header     http.Header
+		RetryAfter time.Duration
+	}{
+		"No headers": {
+			Header: http.Header{},
+		},
+		"Retry-After in seconds": {
+			Header: http.Header{
+				"Retry-After": []string{"30"},
+			},
+			RetryAfter: 30 * time.Second,
+		},
+		"Retry-After with negative seconds": {
+			Header: http.Header{
+				"Retry-After": []string{"-30"},
+			},
+		},
+		"Retry-After as date": {
+			Header: http.Header{
+				"Retry-After": []string{now.Add(time.Hour).Format(http.TimeFormat)},
+			},
+			RetryAfter: time.Hour,
+		},
+		"Retry-After as past date": {
+			Header: http.Header{
+				"Retry-After": []string{now.Add(-time.Hour).Format(http.TimeFormat)},
+			},
+		},
+		"Invalid Retry-After": {
+			Header: http.Header{
+				"Retry-After":       []string{"test"},
+				"X-Ratelimit-Reset": []string{strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			},
+		},
+		"X-RateLimit-Reset": {
+			Header: http.Header{
+				"X-Ratelimit-Reset": []string{strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			},
+			RetryAfter: time.Hour,
+		},
+		"Invalid X-RateLimit-Reset": {
+			Header: http.Header{
+				"X-Ratelimit-Reset": []string{"test"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.RetryAfter, parseRetryAfter(test.Header, now))
+		})
+	}
+}
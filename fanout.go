@@ -0,0 +1,242 @@
+package newsapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// _defaultFanOutConcurrency is the number of requests sent
+// simultaneously by fan-outs, unless specified otherwise.
+const _defaultFanOutConcurrency = 4
+
+// FanOutSources creates a decorator that splits requests with more
+// sources than newsapi accepts into chunks of 20 sources, sends them with
+// up to the specified number of simultaneous requests and merges their
+// results. Non-positive concurrency is replaced by 4.
+//
+// Merged articles are de-duplicated by url and ordered by publication
+// time, newest first, unless everything endpoint parameters request
+// relevancy or popularity order, in which case articles of every chunk
+// are interleaved by their rank. The page and page size apply to every
+// chunk, and the total number of results is the sum of the chunk totals,
+// so it may count duplicates.
+func FanOutSources(concurrency int) Decorator {
+	return func(api API) API {
+		return &fanOutAPI{
+			API:         api,
+			concurrency: concurrency,
+		}
+	}
+}
+
+// fanOutAPI is an API that fans out requests with too many sources.
+type fanOutAPI struct {
+	API
+
+	concurrency int
+}
+
+// Everything splits the sources of the parameters into chunks, if
+// needed, and merges the retrieved articles.
+func (fa *fanOutAPI) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
+	if len(pr.Sources) <= _maxSources {
+		return fa.API.Everything(ctx, pr)
+	}
+
+	chunks := chunkSources(pr.Sources)
+	results := make([]ArticlesResult, len(chunks))
+
+	err := fanOut(ctx, len(chunks), fa.concurrency, func(ctx context.Context, i int) error {
+		chunk := pr
+		chunk.Sources = chunks[i]
+
+		articles, total, err := fa.API.Everything(ctx, chunk)
+		results[i] = ArticlesResult{TotalResults: total, Articles: articles}
+
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	articles, total := mergeArticles(results, pr.SortBy)
+
+	return articles, total, nil
+}
+
+// TopHeadlines splits the sources of the parameters into chunks, if
+// needed, and merges the retrieved articles.
+func (fa *fanOutAPI) TopHeadlines(ctx context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	if len(pr.Sources) <= _maxSources {
+		return fa.API.TopHeadlines(ctx, pr)
+	}
+
+	chunks := chunkSources(pr.Sources)
+	results := make([]ArticlesResult, len(chunks))
+
+	err := fanOut(ctx, len(chunks), fa.concurrency, func(ctx context.Context, i int) error {
+		chunk := pr
+		chunk.Sources = chunks[i]
+
+		articles, total, err := fa.API.TopHeadlines(ctx, chunk)
+		results[i] = ArticlesResult{TotalResults: total, Articles: articles}
+
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	articles, total := mergeArticles(results, SortByPublishedAt)
+
+	return articles, total, nil
+}
+
+// chunkSources splits the sources into chunks newsapi accepts.
+func chunkSources(sources []string) [][]string {
+	chunks := make([][]string, 0, (len(sources)+_maxSources-1)/_maxSources)
+
+	for len(sources) > _maxSources {
+		chunks = append(chunks, sources[:_maxSources:_maxSources])
+		sources = sources[_maxSources:]
+	}
+
+	return append(chunks, sources)
+}
+
+// mergeArticles merges the articles of the results in the specified
+// order, dropping duplicates, and sums their totals. Ties are broken by
+// publication time, newest first, and then by url.
+func mergeArticles(results []ArticlesResult, sortBy SortBy) ([]Article, uint) {
+	var (
+		articles []Article
+		total    uint
+	)
+
+	for _, res := range results {
+		total += res.TotalResults
+	}
+
+	switch sortBy {
+	case SortByRelevancy, SortByPopularity:
+		// Ranks of different requests cannot be compared, so the
+		// articles are interleaved.
+		for rank := 0; ; rank++ {
+			var tier []Article
+
+			for _, res := range results {
+				if rank < len(res.Articles) {
+					tier = append(tier, res.Articles[rank])
+				}
+			}
+
+			if len(tier) == 0 {
+				break
+			}
+
+			sort.SliceStable(tier, func(i, j int) bool {
+				return newerArticle(tier[i], tier[j])
+			})
+
+			articles = append(articles, tier...)
+		}
+	default:
+		for _, res := range results {
+			articles = append(articles, res.Articles...)
+		}
+
+		sort.SliceStable(articles, func(i, j int) bool {
+			return newerArticle(articles[i], articles[j])
+		})
+	}
+
+	return dedupArticles(articles), total
+}
+
+// newerArticle checks if the first article was published later than the
+// second one, comparing urls when both were published at the same time.
+func newerArticle(a, b Article) bool {
+	if !a.PublishedAt.Equal(b.PublishedAt) {
+		return a.PublishedAt.After(b.PublishedAt)
+	}
+
+	return a.URL < b.URL
+}
+
+// dedupArticles drops articles whose url appeared earlier in the list.
+func dedupArticles(articles []Article) []Article {
+	seen := make(map[string]struct{}, len(articles))
+	res := articles[:0]
+
+	for _, a := range articles {
+		if _, ok := seen[a.URL]; ok {
+			continue
+		}
+
+		seen[a.URL] = struct{}{}
+		res = append(res, a)
+	}
+
+	return res
+}
+
+// fanOut calls fn for every index from 0 to n with up to the specified
+// number of simultaneous calls. Once a call fails, the context of the
+// rest is cancelled and the first error that occurred is returned.
+func fanOut(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 {
+		concurrency = _defaultFanOutConcurrency
+	}
+
+	fctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+		sem   = make(chan struct{}, concurrency)
+	)
+
+	started := 0
+
+	for ; started < n; started++ {
+		select {
+		case sem <- struct{}{}:
+		case <-fctx.Done():
+		}
+
+		if fctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(fctx, i); err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}(started)
+	}
+
+	wg.Wait()
+
+	if first != nil {
+		return first
+	}
+
+	if started < n {
+		return ctx.Err()
+	}
+
+	return nil
+}
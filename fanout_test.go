@@ -0,0 +1,211 @@
+package newsapi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourcesAPI returns a single article for every requested source, and
+// records the requested sources.
+type sourcesAPI struct {
+	stubAPI
+
+	mu       sync.Mutex
+	requests [][]string
+	start    time.Time
+}
+
+func (s *sourcesAPI) Everything(_ context.Context, pr EverythingParams) ([]Article, uint, error) {
+	return s.respond(pr.Sources)
+}
+
+func (s *sourcesAPI) TopHeadlines(_ context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	return s.respond(pr.Sources)
+}
+
+func (s *sourcesAPI) respond(sources []string) ([]Article, uint, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, sources)
+	s.mu.Unlock()
+
+	if err := s.call(); err != nil {
+		return nil, 0, err
+	}
+
+	var articles []Article
+
+	for _, src := range sources {
+		i, _ := strconv.Atoi(src)
+		articles = append(articles, Article{
+			Source:      SourceID{ID: src},
+			URL:         "https://example.com/" + strconv.Itoa(i%30),
+			PublishedAt: s.start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	return articles, uint(len(articles)) * 2, nil
+}
+
+func testSources(n int) []string {
+	sources := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sources = append(sources, strconv.Itoa(i))
+	}
+
+	return sources
+}
+
+func Test_FanOutSources(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	api := &sourcesAPI{start: start}
+	fa := Decorate(api, FanOutSources(2))
+
+	articles, total, err := fa.Everything(context.Background(), EverythingParams{Sources: testSources(45)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(90), total)
+	require.Len(t, articles, 30)
+	assert.Equal(t, "44", articles[0].Source.ID)
+	assert.Equal(t, "15", articles[len(articles)-1].Source.ID)
+	assert.ElementsMatch(t, [][]string{testSources(45)[:20], testSources(45)[20:40], testSources(45)[40:]}, api.requests)
+
+	articles, total, err = fa.TopHeadlines(context.Background(), TopHeadlinesParams{Sources: testSources(21)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), total)
+	assert.Len(t, articles, 21)
+	assert.Equal(t, "20", articles[0].Source.ID)
+
+	api.requests = nil
+
+	_, total, err = fa.Everything(context.Background(), EverythingParams{Sources: testSources(20)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(40), total)
+
+	_, total, err = fa.TopHeadlines(context.Background(), TopHeadlinesParams{Sources: testSources(20)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(40), total)
+	assert.Len(t, api.requests, 2)
+
+	api.errs = []error{assert.AnError}
+
+	_, _, err = fa.Everything(context.Background(), EverythingParams{Sources: testSources(21)})
+	assert.Equal(t, assert.AnError, err)
+
+	api.errs = []error{assert.AnError}
+
+	_, _, err = fa.TopHeadlines(context.Background(), TopHeadlinesParams{Sources: testSources(21)})
+	assert.Equal(t, assert.AnError, err)
+}
+
+func Test_chunkSources(t *testing.T) {
+	assert.Equal(t, [][]string{{"a"}}, chunkSources([]string{"a"}))
+	assert.Equal(t, [][]string{testSources(20)}, chunkSources(testSources(20)))
+
+	chunks := chunkSources(testSources(41))
+	assert.Equal(t, [][]string{testSources(41)[:20], testSources(41)[20:40], {"40"}}, chunks)
+
+	// Chunks do not share capacity.
+	chunks[0] = append(chunks[0], "x")
+	assert.Equal(t, "20", chunks[1][0])
+}
+
+func Test_mergeArticles(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	a1 := Article{URL: "1", PublishedAt: start}
+	a2 := Article{URL: "2", PublishedAt: start.Add(time.Minute)}
+	a3 := Article{URL: "3", PublishedAt: start.Add(2 * time.Minute)}
+	a4 := Article{URL: "4", PublishedAt: start.Add(3 * time.Minute)}
+	a5 := Article{URL: "0", PublishedAt: start}
+
+	results := []ArticlesResult{
+		{TotalResults: 10, Articles: []Article{a1, a3}},
+		{TotalResults: 5, Articles: []Article{a2, a4, a1}},
+		{TotalResults: 1, Articles: []Article{a5}},
+	}
+
+	tests := map[string]struct {
+		SortBy   SortBy
+		Articles []Article
+	}{
+		"Default": {
+			Articles: []Article{a4, a3, a2, a5, a1},
+		},
+		"Published at": {
+			SortBy:   SortByPublishedAt,
+			Articles: []Article{a4, a3, a2, a5, a1},
+		},
+		"Relevancy": {
+			SortBy:   SortByRelevancy,
+			Articles: []Article{a2, a5, a1, a4, a3},
+		},
+		"Popularity": {
+			SortBy:   SortByPopularity,
+			Articles: []Article{a2, a5, a1, a4, a3},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			articles, total := mergeArticles(results, test.SortBy)
+			assert.Equal(t, test.Articles, articles)
+			assert.Equal(t, uint(16), total)
+		})
+	}
+}
+
+func Test_fanOut(t *testing.T) {
+	var (
+		running int32
+		max     int32
+		calls   int32
+	)
+
+	err := fanOut(context.Background(), 10, 3, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond)
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), calls)
+	assert.True(t, max <= 3)
+
+	err = fanOut(context.Background(), 10, 0, func(ctx context.Context, i int) error {
+		if i == 0 {
+			return assert.AnError
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = fanOut(ctx, 10, 1, func(ctx context.Context, i int) error {
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
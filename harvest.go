@@ -0,0 +1,250 @@
+package newsapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	// _defaultHarvestMaxResults is the number of results newsapi allows
+	// to page through on the developer plan.
+	_defaultHarvestMaxResults = 100
+
+	// _harvestPageSize is the page size used when harvesting, unless
+	// the parameters specify it.
+	_harvestPageSize = 100
+)
+
+// Harvester retrieves all articles matching everything endpoint
+// parameters, even when there are more of them than newsapi allows to
+// page through. The time window of the parameters is bisected until the
+// number of articles in every slice is within the retrievable limit.
+type Harvester struct {
+	// API is used to retrieve articles.
+	API API
+
+	// MaxResults specifies the number of results newsapi allows to page
+	// through. 100 is default.
+	MaxResults uint
+
+	// MinWindow specifies the shortest time window that is still
+	// bisected. Since newsapi time filters have a resolution of one
+	// second, one second is default and the minimum.
+	MinWindow time.Duration
+}
+
+// SliceCoverage contains coverage information of a single time slice.
+type SliceCoverage struct {
+	// From specifies the start of the slice, inclusive.
+	From time.Time
+
+	// To specifies the end of the slice, inclusive.
+	To time.Time
+
+	// Reported specifies the number of articles newsapi reported to be
+	// available in the slice.
+	Reported uint
+
+	// Retrieved specifies the number of articles retrieved from the
+	// slice, including duplicates.
+	Retrieved uint
+}
+
+// Complete checks if all reported articles of the slice were retrieved.
+func (sc SliceCoverage) Complete() bool {
+	return sc.Retrieved >= sc.Reported
+}
+
+// HarvestReport contains coverage information of a harvest.
+type HarvestReport struct {
+	// Reported specifies the number of articles newsapi reported to be
+	// available in the whole time window.
+	Reported uint
+
+	// Emitted specifies the number of unique articles that were emitted.
+	Emitted uint
+
+	// Slices specifies the time slices that were paged through, oldest
+	// first.
+	Slices []SliceCoverage
+}
+
+// Complete checks if all reported articles of every slice were
+// retrieved.
+func (hr *HarvestReport) Complete() bool {
+	for _, sc := range hr.Slices {
+		if !sc.Complete() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Missing returns the slices from which not all reported articles were
+// retrieved.
+func (hr *HarvestReport) Missing() []SliceCoverage {
+	var res []SliceCoverage
+
+	for _, sc := range hr.Slices {
+		if !sc.Complete() {
+			res = append(res, sc)
+		}
+	}
+
+	return res
+}
+
+// Harvest retrieves all articles matching the parameters and emits them
+// in PublishedAt order, oldest first. From and To times of the
+// parameters must be set. The page of the parameters is ignored; if the
+// page size is not set, the maximum one is used. Articles are
+// de-duplicated by URL. Harvesting stops at the first error returned by
+// the API or by the emit function. The report describes the coverage of
+// the retrieved part of the time window and is returned even when
+// harvesting fails.
+func (h *Harvester) Harvest(ctx context.Context, pr EverythingParams, emit func(Article) error) (*HarvestReport, error) {
+	if pr.From.IsZero() || pr.To.IsZero() {
+		return nil, ErrInvalidHarvestWindow
+	}
+
+	if err := pr.Validate(); err != nil {
+		return nil, err
+	}
+
+	if pr.PageSize == 0 {
+		pr.PageSize = _harvestPageSize
+	}
+
+	hv := &harvest{
+		Harvester: h,
+		emit:      emit,
+		seen:      make(map[string]struct{}),
+		report:    &HarvestReport{},
+	}
+
+	err := hv.slice(ctx, pr, true)
+
+	return hv.report, err
+}
+
+// harvest holds the state of a single harvest.
+type harvest struct {
+	*Harvester
+
+	emit   func(Article) error
+	seen   map[string]struct{}
+	report *HarvestReport
+}
+
+// slice retrieves articles of the time window specified by the
+// parameters, bisecting it if it contains too many articles.
+func (hv *harvest) slice(ctx context.Context, pr EverythingParams, root bool) error {
+	pr.Page = 1
+
+	page, total, err := hv.API.Everything(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	if root {
+		hv.report.Reported = total
+	}
+
+	if total > hv.maxResults() {
+		if left, right, ok := hv.bisect(pr); ok {
+			if err = hv.slice(ctx, left, false); err != nil {
+				return err
+			}
+
+			return hv.slice(ctx, right, false)
+		}
+	}
+
+	limit := total
+	if limit > hv.maxResults() {
+		limit = hv.maxResults()
+	}
+
+	articles := append([]Article(nil), page...)
+
+	for uint(len(articles)) < limit && uint(len(page)) == pr.PageSize {
+		pr.Page++
+
+		page, _, err = hv.API.Everything(ctx, pr)
+		if err != nil {
+			if errors.Is(err, ErrMaximumResultsReached) {
+				break
+			}
+
+			return err
+		}
+
+		articles = append(articles, page...)
+	}
+
+	hv.report.Slices = append(hv.report.Slices, SliceCoverage{
+		From:      pr.From,
+		To:        pr.To,
+		Reported:  total,
+		Retrieved: uint(len(articles)),
+	})
+
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].PublishedAt.Before(articles[j].PublishedAt)
+	})
+
+	for _, a := range articles {
+		if _, ok := hv.seen[a.URL]; ok {
+			continue
+		}
+
+		hv.seen[a.URL] = struct{}{}
+
+		if err = hv.emit(a); err != nil {
+			return err
+		}
+
+		hv.report.Emitted++
+	}
+
+	return nil
+}
+
+// bisect splits the time window of the parameters into two halves that
+// do not overlap. False is returned if the window is too short to be
+// split.
+func (h *Harvester) bisect(pr EverythingParams) (EverythingParams, EverythingParams, bool) {
+	minWindow := h.MinWindow
+	if minWindow < time.Second {
+		minWindow = time.Second
+	}
+
+	window := pr.To.Sub(pr.From)
+	if window < minWindow {
+		return pr, pr, false
+	}
+
+	mid := pr.From.Add(window / 2).Truncate(time.Second)
+	if mid.Before(pr.From) {
+		mid = pr.From
+	}
+
+	left, right := pr, pr
+	left.To = mid
+	right.From = mid.Add(time.Second)
+
+	return left, right, true
+}
+
+// maxResults returns the number of results newsapi allows to page
+// through.
+func (h *Harvester) maxResults() uint {
+	if h.MaxResults == 0 {
+		return _defaultHarvestMaxResults
+	}
+
+	return h.MaxResults
+}
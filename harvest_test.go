@@ -0,0 +1,245 @@
+package newsapi
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corpusAPI serves everything endpoint over a corpus of articles, with
+// the same paging restrictions as newsapi.
+type corpusAPI struct {
+	stubAPI
+
+	mu         sync.Mutex
+	articles   []Article
+	maxResults int
+	requests   []EverythingParams
+}
+
+func (c *corpusAPI) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, pr)
+
+	if err := c.call(); err != nil {
+		return nil, 0, err
+	}
+
+	var matched []Article
+
+	for _, a := range c.articles {
+		if a.PublishedAt.Before(pr.From.Truncate(time.Second)) || a.PublishedAt.After(pr.To.Truncate(time.Second)) {
+			continue
+		}
+
+		matched = append(matched, a)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].PublishedAt.After(matched[j].PublishedAt)
+	})
+
+	offset := int(pr.Page-1) * int(pr.PageSize)
+	if offset >= c.maxResults && offset > 0 {
+		return nil, 0, &Error{HTTPCode: http.StatusUpgradeRequired, APICode: APICodeMaximumResultsReached}
+	}
+
+	end := offset + int(pr.PageSize)
+	if end > c.maxResults {
+		end = c.maxResults
+	}
+
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	if offset > end {
+		offset = end
+	}
+
+	return matched[offset:end], uint(len(matched)), nil
+}
+
+func testCorpus(start time.Time, n int) []Article {
+	articles := make([]Article, 0, n)
+
+	for i := 0; i < n; i++ {
+		articles = append(articles, Article{
+			Title:       time.Duration(i).String(),
+			URL:         "https://example.com/" + time.Duration(i).String(),
+			PublishedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	return articles
+}
+
+func Test_Harvester_Harvest(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	articles := testCorpus(start, 50)
+	// Duplicate article published in a different slice.
+	dup := articles[3]
+	dup.PublishedAt = start.Add(45 * time.Minute)
+	articles = append(articles, dup)
+
+	api := &corpusAPI{
+		articles:   articles,
+		maxResults: 8,
+	}
+	h := &Harvester{
+		API:        api,
+		MaxResults: 8,
+	}
+
+	var emitted []Article
+
+	report, err := h.Harvest(context.Background(), EverythingParams{
+		Query:    "test",
+		From:     start,
+		To:       start.Add(time.Hour),
+		PageSize: 3,
+	}, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(51), report.Reported)
+	assert.Equal(t, uint(50), report.Emitted)
+	assert.True(t, report.Complete())
+	assert.Empty(t, report.Missing())
+
+	require.Len(t, emitted, 50)
+	assert.Equal(t, testCorpus(start, 50), emitted)
+
+	var retrieved uint
+	for i, sc := range report.Slices {
+		assert.True(t, sc.Reported <= 8)
+		retrieved += sc.Retrieved
+
+		if i > 0 {
+			assert.Equal(t, report.Slices[i-1].To.Add(time.Second), sc.From)
+		}
+	}
+
+	assert.Equal(t, uint(51), retrieved)
+	assert.Equal(t, start, report.Slices[0].From)
+	assert.Equal(t, start.Add(time.Hour), report.Slices[len(report.Slices)-1].To)
+
+	for _, pr := range api.requests {
+		assert.Equal(t, "test", pr.Query)
+		assert.Equal(t, uint(3), pr.PageSize)
+	}
+}
+
+func Test_Harvester_Harvest_incomplete(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	articles := testCorpus(start, 3)
+	for i := range articles {
+		articles[i].PublishedAt = start
+	}
+
+	h := &Harvester{
+		API: &corpusAPI{
+			articles:   articles,
+			maxResults: 2,
+		},
+		MaxResults: 2,
+	}
+
+	var emitted int
+
+	report, err := h.Harvest(context.Background(), EverythingParams{
+		Query:    "test",
+		From:     start,
+		To:       start.Add(time.Second),
+		PageSize: 1,
+	}, func(a Article) error {
+		emitted++
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, emitted)
+	assert.False(t, report.Complete())
+	assert.Equal(t, []SliceCoverage{{
+		From:      start,
+		To:        start,
+		Reported:  3,
+		Retrieved: 2,
+	}}, report.Missing())
+}
+
+func Test_Harvester_Harvest_errors(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	pr := EverythingParams{
+		Query: "test",
+		From:  start,
+		To:    start.Add(time.Hour),
+	}
+
+	h := &Harvester{API: &corpusAPI{}}
+
+	_, err := h.Harvest(context.Background(), EverythingParams{Query: "test"}, nil)
+	assert.Equal(t, ErrInvalidHarvestWindow, err)
+
+	_, err = h.Harvest(context.Background(), EverythingParams{From: start, To: start}, nil)
+	assert.ErrorIs(t, err, ErrParamsScopeTooBroad)
+
+	h = &Harvester{API: &corpusAPI{
+		stubAPI:    stubAPI{errs: []error{nil, assert.AnError}},
+		articles:   testCorpus(start, 5),
+		maxResults: 2,
+	}, MaxResults: 2}
+
+	report, err := h.Harvest(context.Background(), pr, func(Article) error {
+		return nil
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, uint(5), report.Reported)
+
+	h = &Harvester{API: &corpusAPI{
+		articles:   testCorpus(start, 5),
+		maxResults: 100,
+	}}
+
+	_, err = h.Harvest(context.Background(), pr, func(Article) error {
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+}
+
+func Test_Harvester_bisect(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	h := &Harvester{}
+
+	left, right, ok := h.bisect(EverythingParams{From: start, To: start.Add(time.Hour)})
+	assert.True(t, ok)
+	assert.Equal(t, start, left.From)
+	assert.Equal(t, start.Add(30*time.Minute), left.To)
+	assert.Equal(t, start.Add(30*time.Minute+time.Second), right.From)
+	assert.Equal(t, start.Add(time.Hour), right.To)
+
+	left, right, ok = h.bisect(EverythingParams{From: start, To: start.Add(time.Second)})
+	assert.True(t, ok)
+	assert.Equal(t, start, left.To)
+	assert.Equal(t, start.Add(time.Second), right.From)
+
+	_, _, ok = h.bisect(EverythingParams{From: start, To: start.Add(time.Millisecond)})
+	assert.False(t, ok)
+
+	h.MinWindow = time.Minute
+
+	_, _, ok = h.bisect(EverythingParams{From: start, To: start.Add(time.Second)})
+	assert.False(t, ok)
+}
@@ -0,0 +1,294 @@
+package newsapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// All available job statuses.
+const (
+	JobCompleted JobStatus = "completed"
+	JobPaused    JobStatus = "paused"
+)
+
+// JobStatus determines the outcome of a harvest job run.
+type JobStatus string
+
+// TimeWindow is a time range with both ends inclusive.
+type TimeWindow struct {
+	// From specifies the start of the window.
+	From time.Time `json:"from"`
+
+	// To specifies the end of the window.
+	To time.Time `json:"to"`
+}
+
+// Checkpoint contains the progress of a harvest job.
+type Checkpoint struct {
+	// Params specifies the harvested parameters.
+	Params EverythingParams `json:"params"`
+
+	// Pending specifies time windows that are yet to be harvested. The
+	// first one is being harvested currently.
+	Pending []TimeWindow `json:"pending"`
+
+	// Page specifies the next page of the current time window. Zero
+	// means the window has not been requested yet.
+	Page uint `json:"page"`
+
+	// Reported specifies the number of articles newsapi reported to be
+	// available in the current time window.
+	Reported uint `json:"reported"`
+
+	// Retrieved specifies the number of articles retrieved from the
+	// current time window so far.
+	Retrieved uint `json:"retrieved"`
+
+	// Seen specifies hashes of article URLs that were already emitted.
+	Seen []string `json:"seen"`
+
+	// Slices specifies coverage of the time windows that were completed.
+	Slices []SliceCoverage `json:"slices"`
+
+	// PausedAt specifies when the job was last paused. It is zero if the
+	// job has never been paused.
+	PausedAt time.Time `json:"pausedAt"`
+
+	// PauseReason specifies the error message of the quota error the
+	// job was last paused by.
+	PauseReason string `json:"pauseReason"`
+
+	// RetryAfter specifies how long to wait before resuming the job, as
+	// advised by newsapi when it was last paused.
+	RetryAfter time.Duration `json:"retryAfter"`
+}
+
+// Done checks if all time windows were harvested.
+func (cp *Checkpoint) Done() bool {
+	return len(cp.Pending) == 0
+}
+
+// HarvestJob is a harvest that persists its progress in a checkpoint file
+// after every retrieved page, so it can be resumed after an interruption.
+// Unlike Harvester, articles are emitted page by page as they are
+// retrieved, so they are not ordered across pages. Quota errors, i.e.
+// rate limiting, an exhausted api key or daily budget, pause the job
+// instead of failing it.
+type HarvestJob struct {
+	Harvester
+
+	// Path specifies the checkpoint file path.
+	Path string
+}
+
+// Run starts the job or, if the checkpoint file exists, resumes it,
+// emitting only the articles that were not emitted before. From and To
+// times of the parameters must be set, and when resuming the parameters
+// must match the ones of the checkpoint. JobPaused status is returned
+// along with a nil error when a quota error occurs; the job can be run
+// again later to resume it.
+func (j *HarvestJob) Run(ctx context.Context, pr EverythingParams, emit func(Article) error) (JobStatus, error) {
+	if pr.From.IsZero() || pr.To.IsZero() {
+		return "", ErrInvalidHarvestWindow
+	}
+
+	if err := pr.Validate(); err != nil {
+		return "", err
+	}
+
+	pr.Page = 0
+	if pr.PageSize == 0 {
+		pr.PageSize = _harvestPageSize
+	}
+
+	cp, err := j.checkpoint(pr)
+	if err != nil {
+		return "", err
+	}
+
+	seen := make(map[string]struct{}, len(cp.Seen))
+	for _, hash := range cp.Seen {
+		seen[hash] = struct{}{}
+	}
+
+	for !cp.Done() {
+		err = j.step(ctx, cp, seen, emit)
+
+		var apiErr *Error
+
+		switch {
+		case err == nil:
+		case IsQuotaError(err):
+			cp.PausedAt = time.Now()
+			cp.PauseReason = err.Error()
+			cp.RetryAfter = 0
+
+			if errors.As(err, &apiErr) {
+				cp.RetryAfter = apiErr.RetryAfter
+			}
+
+			if err = j.save(cp); err != nil {
+				return "", err
+			}
+
+			return JobPaused, nil
+		default:
+			// The page is retrieved again on resume, but the
+			// articles emitted before the failure are already seen,
+			// so they are skipped.
+			if saveErr := j.save(cp); saveErr != nil {
+				return "", saveErr
+			}
+
+			return "", err
+		}
+
+		if err = j.save(cp); err != nil {
+			return "", err
+		}
+	}
+
+	return JobCompleted, nil
+}
+
+// Checkpoint reads the checkpoint file.
+func (j *HarvestJob) Checkpoint() (*Checkpoint, error) {
+	data, err := os.ReadFile(j.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+
+	return &cp, nil
+}
+
+// checkpoint reads the checkpoint file or creates a fresh checkpoint if
+// the file does not exist.
+func (j *HarvestJob) checkpoint(pr EverythingParams) (*Checkpoint, error) {
+	cp, err := j.Checkpoint()
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &Checkpoint{
+			Params: pr,
+			Pending: []TimeWindow{{
+				From: pr.From,
+				To:   pr.To,
+			}},
+		}, nil
+	case err != nil:
+		return nil, err
+	case cp.Params.rawQuery() != pr.rawQuery():
+		return nil, ErrCheckpointMismatch
+	}
+
+	return cp, nil
+}
+
+// step retrieves the next page of the current time window, or bisects
+// the window if it contains too many articles. The page is advanced only
+// after all of its articles are emitted, while every emitted article is
+// recorded as seen immediately.
+func (j *HarvestJob) step(ctx context.Context, cp *Checkpoint, seen map[string]struct{}, emit func(Article) error) error {
+	pr := cp.Params
+	pr.From, pr.To = cp.Pending[0].From, cp.Pending[0].To
+	pr.Page = cp.Page
+
+	if pr.Page == 0 {
+		pr.Page = 1
+	}
+
+	articles, total, err := j.API.Everything(ctx, pr)
+
+	switch {
+	case errors.Is(err, ErrMaximumResultsReached):
+		j.finishWindow(cp)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if cp.Page == 0 {
+		if total > j.maxResults() {
+			if left, right, ok := j.bisect(pr); ok {
+				cp.Pending = append([]TimeWindow{
+					{From: left.From, To: left.To},
+					{From: right.From, To: right.To},
+				}, cp.Pending[1:]...)
+
+				return nil
+			}
+		}
+
+		cp.Reported = total
+	}
+
+	for _, a := range articles {
+		hash := shortHash(a.URL)
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+
+		if err = emit(a); err != nil {
+			return err
+		}
+
+		seen[hash] = struct{}{}
+		cp.Seen = append(cp.Seen, hash)
+	}
+
+	cp.Page = pr.Page + 1
+	cp.Retrieved += uint(len(articles))
+
+	limit := cp.Reported
+	if limit > j.maxResults() {
+		limit = j.maxResults()
+	}
+
+	if cp.Retrieved >= limit || uint(len(articles)) < pr.PageSize {
+		j.finishWindow(cp)
+	}
+
+	return nil
+}
+
+// finishWindow records coverage of the current time window and moves on
+// to the next one.
+func (j *HarvestJob) finishWindow(cp *Checkpoint) {
+	cp.Slices = append(cp.Slices, SliceCoverage{
+		From:      cp.Pending[0].From,
+		To:        cp.Pending[0].To,
+		Reported:  cp.Reported,
+		Retrieved: cp.Retrieved,
+	})
+
+	cp.Pending = cp.Pending[1:]
+	cp.Page = 0
+	cp.Reported = 0
+	cp.Retrieved = 0
+}
+
+// save writes the checkpoint to the checkpoint file.
+func (j *HarvestJob) save(cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(j.Path, data)
+}
+
+// shortHash returns a short hash of the string.
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
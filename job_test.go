@@ -0,0 +1,221 @@
+package newsapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HarvestJob_Run(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	articles := testCorpus(start, 50)
+	// Duplicate article published in a different slice.
+	dup := articles[3]
+	dup.PublishedAt = start.Add(45 * time.Minute)
+	articles = append(articles, dup)
+
+	rateLimited := &Error{
+		HTTPCode:   http.StatusTooManyRequests,
+		APICode:    APICodeRateLimited,
+		Message:    "test",
+		RetryAfter: time.Minute,
+	}
+
+	api := &corpusAPI{
+		stubAPI: stubAPI{errs: []error{
+			nil, nil, nil, nil, nil, nil, nil, rateLimited,
+			nil, nil, nil, nil, nil, rateLimited,
+		}},
+		articles:   articles,
+		maxResults: 8,
+	}
+	j := &HarvestJob{
+		Harvester: Harvester{
+			API:        api,
+			MaxResults: 8,
+		},
+		Path: filepath.Join(t.TempDir(), "job.json"),
+	}
+	pr := EverythingParams{
+		Query:    "test",
+		From:     start,
+		To:       start.Add(time.Hour),
+		PageSize: 3,
+	}
+
+	var emitted []Article
+
+	emit := func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	}
+
+	status, err := j.Run(context.Background(), pr, emit)
+	require.NoError(t, err)
+	assert.Equal(t, JobPaused, status)
+
+	cp, err := j.Checkpoint()
+	require.NoError(t, err)
+	assert.False(t, cp.Done())
+	assert.Equal(t, rateLimited.Error(), cp.PauseReason)
+	assert.Equal(t, time.Minute, cp.RetryAfter)
+	assert.False(t, cp.PausedAt.IsZero())
+	assert.Len(t, cp.Seen, len(emitted))
+
+	status, err = j.Run(context.Background(), pr, emit)
+	require.NoError(t, err)
+	assert.Equal(t, JobPaused, status)
+
+	status, err = j.Run(context.Background(), pr, emit)
+	require.NoError(t, err)
+	assert.Equal(t, JobCompleted, status)
+
+	sort.SliceStable(emitted, func(i, j int) bool {
+		return emitted[i].PublishedAt.Before(emitted[j].PublishedAt)
+	})
+	assert.Equal(t, testCorpus(start, 50), emitted)
+
+	cp, err = j.Checkpoint()
+	require.NoError(t, err)
+	assert.True(t, cp.Done())
+	assert.Len(t, cp.Seen, 50)
+
+	var retrieved uint
+	for i, sc := range cp.Slices {
+		assert.True(t, sc.Reported <= 8)
+		retrieved += sc.Retrieved
+
+		if i > 0 {
+			assert.Equal(t, cp.Slices[i-1].To.Add(time.Second), sc.From)
+		}
+	}
+
+	assert.Equal(t, uint(51), retrieved)
+
+	// Completed job does not emit anything.
+	calls := api.calls
+
+	status, err = j.Run(context.Background(), pr, func(Article) error {
+		return assert.AnError
+	})
+	require.NoError(t, err)
+	assert.Equal(t, JobCompleted, status)
+	assert.Equal(t, calls, api.calls)
+}
+
+func Test_HarvestJob_Run_errors(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	pr := EverythingParams{
+		Query: "test",
+		From:  start,
+		To:    start.Add(time.Hour),
+	}
+	path := filepath.Join(t.TempDir(), "job.json")
+
+	j := &HarvestJob{Harvester: Harvester{API: &corpusAPI{}}, Path: path}
+
+	_, err := j.Run(context.Background(), EverythingParams{Query: "test"}, nil)
+	assert.Equal(t, ErrInvalidHarvestWindow, err)
+
+	_, err = j.Run(context.Background(), EverythingParams{From: start, To: start}, nil)
+	assert.ErrorIs(t, err, ErrParamsScopeTooBroad)
+
+	j = &HarvestJob{Harvester: Harvester{API: &corpusAPI{
+		stubAPI:    stubAPI{errs: []error{nil, assert.AnError}},
+		articles:   testCorpus(start, 5),
+		maxResults: 2,
+	}, MaxResults: 2}, Path: path}
+
+	_, err = j.Run(context.Background(), pr, func(Article) error {
+		return nil
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	pr2 := pr
+	pr2.Query = "other"
+
+	_, err = j.Run(context.Background(), pr2, nil)
+	assert.Equal(t, ErrCheckpointMismatch, err)
+
+	j.Path = filepath.Join(t.TempDir(), "job.json")
+	j.API = &corpusAPI{
+		articles:   testCorpus(start, 5),
+		maxResults: 100,
+	}
+
+	_, err = j.Run(context.Background(), pr, func(Article) error {
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	require.NoError(t, os.WriteFile(j.Path, []byte("{"), 0o600))
+
+	_, err = j.Run(context.Background(), pr, nil)
+	assert.Error(t, err)
+
+	j.Path = filepath.Join(t.TempDir(), "missing", "job.json")
+
+	_, err = j.Run(context.Background(), pr, func(Article) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+func Test_HarvestJob_Run_emitError(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	api := &corpusAPI{
+		articles:   testCorpus(start, 10),
+		maxResults: 100,
+	}
+	j := &HarvestJob{
+		Harvester: Harvester{API: api},
+		Path:      filepath.Join(t.TempDir(), "job.json"),
+	}
+	pr := EverythingParams{
+		Query:    "test",
+		From:     start,
+		To:       start.Add(time.Hour),
+		PageSize: 4,
+	}
+
+	var emitted []Article
+
+	_, err := j.Run(context.Background(), pr, func(a Article) error {
+		if len(emitted) == 6 {
+			return assert.AnError
+		}
+
+		emitted = append(emitted, a)
+
+		return nil
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Len(t, emitted, 6)
+
+	cp, err := j.Checkpoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), cp.Page)
+	assert.Equal(t, uint(4), cp.Retrieved)
+	assert.Len(t, cp.Seen, 6)
+
+	status, err := j.Run(context.Background(), pr, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, JobCompleted, status)
+
+	sort.SliceStable(emitted, func(i, j int) bool {
+		return emitted[i].PublishedAt.Before(emitted[j].PublishedAt)
+	})
+	assert.Equal(t, testCorpus(start, 10), emitted)
+}
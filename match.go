@@ -0,0 +1,76 @@
+package newsapi
+
+import (
+	"github.com/jellydator/newsapi-go/query"
+)
+
+// ArticleMatcher evaluates everything endpoint queries against articles
+// locally, e.g. to split merged results or to filter archived articles.
+type ArticleMatcher struct {
+	matcher  *query.Matcher
+	searchIn []SearchIn
+}
+
+// NewArticleMatcher parses the query, which uses the same advanced search
+// syntax as EverythingParams.Query, and creates a matcher for it. Search
+// keys restrict the article parts that are searched; if none are
+// provided, title, description and content are searched.
+func NewArticleMatcher(q string, searchIn ...SearchIn) (*ArticleMatcher, error) {
+	for _, si := range searchIn {
+		if !si.isValid() {
+			return nil, ErrInvalidSearchIn
+		}
+	}
+
+	m, err := query.Compile(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArticleMatcher{
+		matcher:  m,
+		searchIn: searchIn,
+	}, nil
+}
+
+// Match evaluates the query against the article. The result contains the
+// keywords and phrases that were found in the article, which can be used
+// for highlighting.
+func (am *ArticleMatcher) Match(a Article) query.Result {
+	return am.matcher.Match(articleFields(a, am.searchIn)...)
+}
+
+// Filter returns the articles that match the query.
+func (am *ArticleMatcher) Filter(articles []Article) []Article {
+	var res []Article
+
+	for _, a := range articles {
+		if am.Match(a).Matched {
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+// articleFields returns the article parts specified by the search keys.
+func articleFields(a Article, searchIn []SearchIn) []string {
+	if len(searchIn) == 0 {
+		return []string{a.Title, a.Description, a.Content}
+	}
+
+	fields := make([]string, 0, len(searchIn))
+
+	for _, si := range searchIn {
+		switch si {
+		case SearchInTitle:
+			fields = append(fields, a.Title)
+		case SearchInDescription:
+			fields = append(fields, a.Description)
+		case SearchInContent:
+			fields = append(fields, a.Content)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,90 @@
+package newsapi
+
+import (
+	"testing"
+
+	"github.com/jellydator/newsapi-go/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewArticleMatcher(t *testing.T) {
+	m, err := NewArticleMatcher("bitcoin", SearchInTitle)
+	require.NoError(t, err)
+	assert.Equal(t, []SearchIn{SearchInTitle}, m.searchIn)
+
+	_, err = NewArticleMatcher("bitcoin", SearchIn("test"))
+	assert.Equal(t, ErrInvalidSearchIn, err)
+
+	_, err = NewArticleMatcher("(bitcoin")
+	assert.IsType(t, &query.SyntaxError{}, err)
+}
+
+func Test_ArticleMatcher_Match(t *testing.T) {
+	article := Article{
+		Title:       "Bitcoin price falls",
+		Description: "Crypto markets are down",
+		Content:     "Ethereum follows",
+	}
+
+	tests := map[string]struct {
+		Query    string
+		SearchIn []SearchIn
+		Result   query.Result
+	}{
+		"All parts": {
+			Query: "bitcoin AND crypto AND ethereum",
+			Result: query.Result{
+				Matched: true,
+				Terms:   []string{"bitcoin", "crypto", "ethereum"},
+			},
+		},
+		"Title": {
+			Query:    "bitcoin OR crypto",
+			SearchIn: []SearchIn{SearchInTitle},
+			Result: query.Result{
+				Matched: true,
+				Terms:   []string{"bitcoin"},
+			},
+		},
+		"Title excludes description": {
+			Query:    "crypto",
+			SearchIn: []SearchIn{SearchInTitle},
+		},
+		"Description and content": {
+			Query:    "crypto AND ethereum NOT bitcoin",
+			SearchIn: []SearchIn{SearchInDescription, SearchInContent},
+			Result: query.Result{
+				Matched: true,
+				Terms:   []string{"crypto", "ethereum"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := NewArticleMatcher(test.Query, test.SearchIn...)
+			require.NoError(t, err)
+			assert.Equal(t, test.Result, m.Match(article))
+		})
+	}
+}
+
+func Test_ArticleMatcher_Filter(t *testing.T) {
+	m, err := NewArticleMatcher("bitcoin -ethereum")
+	require.NoError(t, err)
+
+	articles := m.Filter([]Article{
+		{Title: "Bitcoin"},
+		{Title: "Bitcoin and Ethereum"},
+		{Title: "Litecoin"},
+		{Title: "bitcoin"},
+	})
+
+	assert.Equal(t, []Article{{Title: "Bitcoin"}, {Title: "bitcoin"}}, articles)
+	assert.Nil(t, m.Filter(nil))
+}
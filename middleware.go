@@ -0,0 +1,95 @@
+package newsapi
+
+import (
+	"net/http"
+)
+
+// ArticlesResult is the decoded result of everything and top headlines
+// endpoints.
+type ArticlesResult struct {
+	// TotalResults specifies the number of available articles.
+	TotalResults uint `json:"totalResults"`
+
+	// Articles specifies the articles of the requested page.
+	Articles []Article `json:"articles"`
+}
+
+// SourcesResult is the decoded result of sources endpoint.
+type SourcesResult struct {
+	// Sources specifies the matching sources.
+	Sources []Source `json:"sources"`
+}
+
+// Exchange contains a single request round trip passing through the
+// middleware chain.
+type Exchange struct {
+	// Endpoint specifies the requested endpoint.
+	Endpoint Endpoint
+
+	// Params specifies the validated parameters the request was built
+	// from. It is one of EverythingParams, TopHeadlinesParams and
+	// SourceParams.
+	Params interface{}
+
+	// Request specifies the request to be sent. Middleware may modify
+	// it, e.g. by adding headers, before calling the next handler.
+	Request *http.Request
+
+	// Response specifies the received response. It is set by the
+	// innermost handler once the response is received and its body is
+	// already consumed by the time the handler returns. It is nil if the
+	// request failed or was not sent.
+	Response *http.Response
+
+	// Result specifies where the response is decoded into. It is either
+	// *ArticlesResult or *SourcesResult and may be modified by
+	// middleware once the next handler returns.
+	Result interface{}
+}
+
+// Handler performs the exchange round trip.
+type Handler func(ex *Exchange) error
+
+// Middleware wraps the handler with additional behaviour, e.g. auditing,
+// header injection, tracing or response rewriting. Middleware may also
+// skip calling the next handler and fill the exchange result on its own.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends the middleware to the client's middleware
+// chain. The first middleware is the outermost one, so it sees every
+// exchange first. Middleware runs once per exchange, i.e. once for
+// every endpoint request that is built, around caching, retries and rate
+// limiting, whether the response comes from the cache or the network.
+// Sources with multi-valued filters builds a request for every
+// combination of the values, so middleware runs for each of them.
+func WithMiddleware(mws ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.mws = append(c.mws, mws...)
+	}
+}
+
+// handler returns the client's round trip handler wrapped by the
+// middleware chain.
+func (c *Client) handler() Handler {
+	h := Handler(c.roundTrip)
+
+	for i := len(c.mws) - 1; i >= 0; i-- {
+		h = c.mws[i](h)
+	}
+
+	return h
+}
+
+// paramsValue returns the params struct the params point to.
+func paramsValue(pr params) interface{} {
+	switch pr := pr.(type) {
+	case *EverythingParams:
+		return *pr
+	case *TopHeadlinesParams:
+		return *pr
+	case *SourceParams:
+		return *pr
+	}
+
+	return pr
+}
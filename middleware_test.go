@@ -0,0 +1,120 @@
+package newsapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithMiddleware(t *testing.T) {
+	mw := func(next Handler) Handler {
+		return next
+	}
+
+	c := &Client{}
+	WithMiddleware(mw)(c)
+	WithMiddleware(mw, mw)(c)
+
+	assert.Len(t, c.mws, 3)
+}
+
+func Test_Client_handler(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "test/everything", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "123", req.Header.Get("X-Trace-Id"))
+
+		return httpmock.NewStringResponse(
+			http.StatusOK,
+			`{"status":"ok","totalResults":2,"articles":[{"title":"1"},{"title":"2"}]}`,
+		), nil
+	})
+
+	var order []string
+
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithMiddleware(
+			func(next Handler) Handler {
+				return func(ex *Exchange) error {
+					order = append(order, "audit")
+
+					assert.Equal(t, EndpointEverything, ex.Endpoint)
+					assert.Equal(t, EverythingParams{Query: "bitcoin"}, ex.Params)
+
+					err := next(ex)
+					require.NotNil(t, ex.Response)
+					assert.Equal(t, http.StatusOK, ex.Response.StatusCode)
+
+					return err
+				}
+			},
+			func(next Handler) Handler {
+				return func(ex *Exchange) error {
+					order = append(order, "trace")
+					ex.Request.Header.Set("X-Trace-Id", "123")
+
+					return next(ex)
+				}
+			},
+			func(next Handler) Handler {
+				return func(ex *Exchange) error {
+					order = append(order, "rewrite")
+
+					if err := next(ex); err != nil {
+						return err
+					}
+
+					res := ex.Result.(*ArticlesResult)
+					res.Articles = res.Articles[1:]
+
+					return nil
+				}
+			},
+		),
+	)
+
+	articles, total, err := client.Everything(context.Background(), EverythingParams{Query: "bitcoin"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Article{{Title: "2"}}, articles)
+	assert.Equal(t, uint(2), total)
+	assert.Equal(t, []string{"audit", "trace", "rewrite"}, order)
+}
+
+func Test_Client_handler_shortCircuit(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithMiddleware(func(next Handler) Handler {
+			return func(ex *Exchange) error {
+				if pr, ok := ex.Params.(SourceParams); ok && len(pr.Countries) == 0 {
+					ex.Result.(*SourcesResult).Sources = []Source{{SourceID: SourceID{ID: "bbc"}}}
+					return nil
+				}
+
+				return next(ex)
+			}
+		}),
+	)
+
+	sources, err := client.Sources(context.Background(), SourceParams{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Source{{SourceID: SourceID{ID: "bbc"}}}, sources)
+	assert.Zero(t, transport.GetTotalCallCount())
+}
+
+func Test_paramsValue(t *testing.T) {
+	assert.Equal(t, EverythingParams{Query: "1"}, paramsValue(&EverythingParams{Query: "1"}))
+	assert.Equal(t, TopHeadlinesParams{Query: "1"}, paramsValue(&TopHeadlinesParams{Query: "1"}))
+	assert.Equal(t, SourceParams{}, paramsValue(&SourceParams{}))
+}
@@ -0,0 +1,230 @@
+package newsapi
+
+import (
+	"context"
+)
+
+// TopHeadlinesMultiParams contains top headlines filters with multiple
+// values. Every combination of the values is requested separately.
+type TopHeadlinesMultiParams struct {
+	// Query is used to filter articles' text of every combination.
+	Query string
+
+	// Countries specifies the countries to request. If left empty, all
+	// countries are used.
+	Countries []Country
+
+	// Categories specifies the categories to request. If left empty,
+	// all categories are used.
+	Categories []Category
+
+	// Languages specifies the languages to request. If left empty, all
+	// languages are used.
+	Languages []Language
+
+	// PageSize specifies the number of results of every combination.
+	// 20 is default, 100 is the maximum.
+	PageSize uint
+
+	// Page specifies the page of every combination.
+	Page uint
+
+	// Concurrency specifies the maximum number of simultaneous requests.
+	// 4 is default.
+	Concurrency int
+}
+
+// expand returns the parameters of every combination of the values,
+// ordered by country, category and language.
+func (mp *TopHeadlinesMultiParams) expand() []TopHeadlinesParams {
+	countries := mp.Countries
+	if len(countries) == 0 {
+		countries = []Country{""}
+	}
+
+	categories := mp.Categories
+	if len(categories) == 0 {
+		categories = []Category{""}
+	}
+
+	languages := mp.Languages
+	if len(languages) == 0 {
+		languages = []Language{""}
+	}
+
+	res := make([]TopHeadlinesParams, 0, len(countries)*len(categories)*len(languages))
+
+	for _, country := range countries {
+		for _, category := range categories {
+			for _, language := range languages {
+				res = append(res, TopHeadlinesParams{
+					Query:    mp.Query,
+					Country:  country,
+					Category: category,
+					Language: language,
+					PageSize: mp.PageSize,
+					Page:     mp.Page,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// HeadlinesGroup contains top headlines of a single combination of
+// multi params values.
+type HeadlinesGroup struct {
+	// Country specifies the country of the group.
+	Country Country
+
+	// Category specifies the category of the group.
+	Category Category
+
+	// Language specifies the language of the group.
+	Language Language
+
+	// Articles specifies the retrieved articles.
+	Articles []Article
+
+	// TotalResults specifies the number of available articles.
+	TotalResults uint
+
+	// Err specifies the error the group failed with, if any. Groups
+	// whose parameters are invalid fail without sending a request.
+	Err error
+}
+
+// TopHeadlinesMulti retrieves top headlines of every combination of the
+// countries, categories and languages, with up to the configured number
+// of simultaneous requests. Failures are reported per group, so a single
+// failing combination does not fail the rest.
+func TopHeadlinesMulti(ctx context.Context, api API, pr TopHeadlinesMultiParams) []HeadlinesGroup {
+	expanded := pr.expand()
+	groups := make([]HeadlinesGroup, len(expanded))
+
+	for i, thp := range expanded {
+		groups[i] = HeadlinesGroup{
+			Country:  thp.Country,
+			Category: thp.Category,
+			Language: thp.Language,
+		}
+	}
+
+	called := make([]bool, len(expanded))
+
+	err := fanOut(ctx, len(expanded), pr.Concurrency, func(ctx context.Context, i int) error {
+		group := &groups[i]
+		called[i] = true
+
+		if group.Err = expanded[i].Validate(); group.Err != nil {
+			return nil
+		}
+
+		group.Articles, group.TotalResults, group.Err = api.TopHeadlines(ctx, expanded[i])
+
+		return nil
+	})
+	if err != nil {
+		// Groups that were not requested fail with the context error.
+		for i := range groups {
+			if !called[i] {
+				groups[i].Err = err
+			}
+		}
+	}
+
+	return groups
+}
+
+// EverythingMultiParams contains everything endpoint parameters with
+// multiple languages. The same query is requested for every language.
+type EverythingMultiParams struct {
+	// EverythingParams specifies the parameters shared by every
+	// language. Its Language field is ignored.
+	EverythingParams
+
+	// Languages specifies the languages to request. Duplicates are
+	// ignored.
+	Languages []Language
+
+	// Concurrency specifies the maximum number of simultaneous requests.
+	// 4 is default.
+	Concurrency int
+}
+
+// EverythingMultiResult contains merged articles of multiple languages.
+type EverythingMultiResult struct {
+	// Articles specifies the merged and de-duplicated articles.
+	Articles []Article
+
+	// TotalResults specifies the sum of available articles of every
+	// language.
+	TotalResults uint
+
+	// Totals specifies the number of available articles of every
+	// language.
+	Totals map[Language]uint
+}
+
+// EverythingMulti retrieves articles of every language, with up to the
+// configured number of simultaneous requests, and merges them in the
+// requested order. Articles published later come first, unless relevancy
+// or popularity order is requested, in which case articles of every
+// language are interleaved by their rank. Ties are broken by publication
+// time and then by url. The first failure fails the whole call.
+func EverythingMulti(ctx context.Context, api API, pr EverythingMultiParams) (*EverythingMultiResult, error) {
+	var languages []Language
+
+	seen := make(map[Language]struct{}, len(pr.Languages))
+
+	for _, lang := range pr.Languages {
+		if _, ok := seen[lang]; ok {
+			continue
+		}
+
+		seen[lang] = struct{}{}
+		languages = append(languages, lang)
+	}
+
+	if len(languages) == 0 {
+		return nil, ErrNoLanguages
+	}
+
+	expanded := make([]EverythingParams, 0, len(languages))
+
+	for _, lang := range languages {
+		ep := pr.EverythingParams
+		ep.Language = lang
+
+		if err := ep.Validate(); err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, ep)
+	}
+
+	results := make([]ArticlesResult, len(expanded))
+
+	err := fanOut(ctx, len(expanded), pr.Concurrency, func(ctx context.Context, i int) error {
+		articles, total, err := api.Everything(ctx, expanded[i])
+		results[i] = ArticlesResult{TotalResults: total, Articles: articles}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &EverythingMultiResult{
+		Totals: make(map[Language]uint, len(languages)),
+	}
+
+	for i, lang := range languages {
+		res.Totals[lang] = results[i].TotalResults
+	}
+
+	res.Articles, res.TotalResults = mergeArticles(results, pr.SortBy)
+
+	return res, nil
+}
@@ -0,0 +1,194 @@
+package newsapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headlinesAPI returns a single article named after the requested
+// country and category, failing for the specified country.
+type headlinesAPI struct {
+	stubAPI
+
+	mu       sync.Mutex
+	requests []TopHeadlinesParams
+	failing  Country
+}
+
+func (h *headlinesAPI) TopHeadlines(_ context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	h.mu.Lock()
+	h.requests = append(h.requests, pr)
+	h.mu.Unlock()
+
+	if pr.Country != "" && pr.Country == h.failing {
+		return nil, 0, assert.AnError
+	}
+
+	return []Article{{Title: string(pr.Country) + "/" + string(pr.Category)}}, 1, nil
+}
+
+// languagesAPI returns predefined articles of every language.
+type languagesAPI struct {
+	stubAPI
+
+	mu       sync.Mutex
+	articles map[Language][]Article
+	requests []EverythingParams
+}
+
+func (l *languagesAPI) Everything(_ context.Context, pr EverythingParams) ([]Article, uint, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests = append(l.requests, pr)
+
+	if err := l.call(); err != nil {
+		return nil, 0, err
+	}
+
+	articles := l.articles[pr.Language]
+
+	return articles, uint(len(articles)) * 10, nil
+}
+
+func Test_TopHeadlinesMultiParams_expand(t *testing.T) {
+	mp := TopHeadlinesMultiParams{
+		Query:      "test",
+		Countries:  []Country{CountryUnitedStates, CountryGermany},
+		Categories: []Category{CategoryBusiness, CategorySports},
+		PageSize:   10,
+		Page:       2,
+	}
+
+	assert.Equal(t, []TopHeadlinesParams{
+		{Query: "test", Country: CountryUnitedStates, Category: CategoryBusiness, PageSize: 10, Page: 2},
+		{Query: "test", Country: CountryUnitedStates, Category: CategorySports, PageSize: 10, Page: 2},
+		{Query: "test", Country: CountryGermany, Category: CategoryBusiness, PageSize: 10, Page: 2},
+		{Query: "test", Country: CountryGermany, Category: CategorySports, PageSize: 10, Page: 2},
+	}, mp.expand())
+
+	mp = TopHeadlinesMultiParams{Languages: []Language{LanguageEnglish, LanguageGerman}}
+	assert.Equal(t, []TopHeadlinesParams{
+		{Language: LanguageEnglish},
+		{Language: LanguageGerman},
+	}, mp.expand())
+
+	assert.Equal(t, []TopHeadlinesParams{{}}, (&TopHeadlinesMultiParams{}).expand())
+}
+
+func Test_TopHeadlinesMulti(t *testing.T) {
+	api := &headlinesAPI{failing: CountryGermany}
+
+	groups := TopHeadlinesMulti(context.Background(), api, TopHeadlinesMultiParams{
+		Countries:   []Country{CountryUnitedStates, CountryGermany, "xx"},
+		Categories:  []Category{CategoryBusiness, CategorySports},
+		Concurrency: 2,
+	})
+	require.Len(t, groups, 6)
+	assert.Len(t, api.requests, 4)
+
+	assert.Equal(t, HeadlinesGroup{
+		Country:      CountryUnitedStates,
+		Category:     CategoryBusiness,
+		Articles:     []Article{{Title: "us/business"}},
+		TotalResults: 1,
+	}, groups[0])
+	assert.Equal(t, HeadlinesGroup{
+		Country:      CountryUnitedStates,
+		Category:     CategorySports,
+		Articles:     []Article{{Title: "us/sports"}},
+		TotalResults: 1,
+	}, groups[1])
+	assert.Equal(t, HeadlinesGroup{
+		Country:  CountryGermany,
+		Category: CategoryBusiness,
+		Err:      assert.AnError,
+	}, groups[2])
+	assert.Equal(t, assert.AnError, groups[3].Err)
+	assert.ErrorIs(t, groups[4].Err, ErrInvalidCountry)
+	assert.ErrorIs(t, groups[5].Err, ErrInvalidCountry)
+
+	groups = TopHeadlinesMulti(context.Background(), api, TopHeadlinesMultiParams{})
+	require.Len(t, groups, 1)
+	assert.ErrorIs(t, groups[0].Err, ErrParamsScopeTooBroad)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	groups = TopHeadlinesMulti(ctx, api, TopHeadlinesMultiParams{
+		Countries: []Country{CountryUnitedStates},
+	})
+	require.Len(t, groups, 1)
+	assert.Equal(t, context.Canceled, groups[0].Err)
+}
+
+func Test_EverythingMulti(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	en1 := Article{URL: "en1", PublishedAt: start}
+	en2 := Article{URL: "en2", PublishedAt: start.Add(2 * time.Minute)}
+	de1 := Article{URL: "de1", PublishedAt: start}
+	de2 := Article{URL: "de2", PublishedAt: start.Add(time.Minute)}
+
+	api := &languagesAPI{
+		articles: map[Language][]Article{
+			LanguageEnglish: {en1, en2},
+			LanguageGerman:  {de1, de2, en2},
+		},
+	}
+
+	res, err := EverythingMulti(context.Background(), api, EverythingMultiParams{
+		EverythingParams: EverythingParams{
+			Query:    "test",
+			Language: LanguageFrench,
+		},
+		Languages:   []Language{LanguageEnglish, LanguageGerman, LanguageEnglish},
+		Concurrency: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &EverythingMultiResult{
+		Articles:     []Article{en2, de2, de1, en1},
+		TotalResults: 50,
+		Totals: map[Language]uint{
+			LanguageEnglish: 20,
+			LanguageGerman:  30,
+		},
+	}, res)
+	assert.Equal(t, []EverythingParams{
+		{Query: "test", Language: LanguageEnglish},
+		{Query: "test", Language: LanguageGerman},
+	}, api.requests)
+
+	res, err = EverythingMulti(context.Background(), api, EverythingMultiParams{
+		EverythingParams: EverythingParams{
+			Query:  "test",
+			SortBy: SortByRelevancy,
+		},
+		Languages: []Language{LanguageEnglish, LanguageGerman},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Article{de1, en1, en2, de2}, res.Articles)
+
+	_, err = EverythingMulti(context.Background(), api, EverythingMultiParams{
+		EverythingParams: EverythingParams{Query: "test"},
+	})
+	assert.Equal(t, ErrNoLanguages, err)
+
+	_, err = EverythingMulti(context.Background(), api, EverythingMultiParams{
+		EverythingParams: EverythingParams{Query: "test"},
+		Languages:        []Language{LanguageEnglish, "xx"},
+	})
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+
+	api.errs = []error{assert.AnError}
+
+	_, err = EverythingMulti(context.Background(), api, EverythingMultiParams{
+		EverythingParams: EverythingParams{Query: "test"},
+		Languages:        []Language{LanguageEnglish, LanguageGerman},
+	})
+	assert.Equal(t, assert.AnError, err)
+}
@@ -11,11 +11,26 @@ import (
 
 const _defaultBaseURL = "https://newsapi.org/v2/"
 
+// All available endpoints.
+const (
+	EndpointEverything   Endpoint = "everything"
+	EndpointTopHeadlines Endpoint = "top-headlines"
+	EndpointSources      Endpoint = "top-headlines/sources"
+)
+
+// Endpoint determines the newsapi endpoint path, relative to the base url.
+type Endpoint string
+
 // Client handles request sending to newsapi.
 type Client struct {
 	apiKey  string
 	baseURL string
 	client  *http.Client
+	retry   *RetryPolicy
+	limiter *rateLimiter
+	budget  *budget
+	cache   *responseCache
+	mws     []Middleware
 }
 
 // ClientOption is used to set client configuration options.
@@ -59,7 +74,7 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 // Endpoint documentation can be found here:
 // https://newsapi.org/docs/endpoints/everything
 func (c *Client) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
-	return c.getArticles(ctx, "everything", &pr)
+	return c.getArticles(ctx, EndpointEverything, &pr)
 }
 
 // TopHeadlines retrieves top headlines articles by the provided parameters.
@@ -69,114 +84,190 @@ func (c *Client) Everything(ctx context.Context, pr EverythingParams) ([]Article
 // Endpoint documentation can be found here:
 // https://newsapi.org/docs/endpoints/top-headlines
 func (c *Client) TopHeadlines(ctx context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
-	return c.getArticles(ctx, "top-headlines", &pr)
+	return c.getArticles(ctx, EndpointTopHeadlines, &pr)
 }
 
 // Sources retrieves available sources for top headlines and everything
-// endpoints by the provided parameters.
+// endpoints by the provided parameters. When any filter has multiple
+// values, a request is sent for every combination of the values, up to
+// 4 at a time, and the sources are merged, de-duplicated by id, in the
+// order of the combinations. Repeated values are ignored, so the number
+// of requests is the product of the numbers of distinct values of the
+// filters, e.g. 2 categories and 3 languages result in 6 requests, and
+// every one of them counts against the budget set by WithDailyBudget.
 // Endpoint documentation can be found here:
 // https://newsapi.org/docs/endpoints/sources
 func (c *Client) Sources(ctx context.Context, pr SourceParams) ([]Source, error) {
-	statusCode, body, err := c.get(
-		ctx,
-		"top-headlines/sources",
-		&pr,
-	)
-	if err != nil {
+	expanded := pr.expand()
+	if len(expanded) == 1 {
+		var res SourcesResult
+
+		if err := c.exchange(ctx, EndpointSources, &expanded[0], &res); err != nil {
+			return nil, err
+		}
+
+		return res.Sources, nil
+	}
+
+	if err := pr.Validate(); err != nil {
 		return nil, err
 	}
-	defer body.Close()
 
-	data := struct {
-		Status  string   `json:"status"`
-		Code    string   `json:"code"`
-		Sources []Source `json:"sources"`
-		Message string   `json:"message"`
-	}{}
+	results := make([]SourcesResult, len(expanded))
 
-	if err = json.NewDecoder(body).Decode(&data); err != nil {
+	err := fanOut(ctx, len(expanded), 0, func(ctx context.Context, i int) error {
+		return c.exchange(ctx, EndpointSources, &expanded[i], &results[i])
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if data.Status != "ok" {
-		return nil, &Error{
-			HTTPCode: statusCode,
-			APICode:  data.Code,
-			Message:  data.Message,
+	var sources []Source
+
+	seen := make(map[string]struct{})
+
+	for _, res := range results {
+		for _, src := range res.Sources {
+			if _, ok := seen[src.ID]; ok {
+				continue
+			}
+
+			seen[src.ID] = struct{}{}
+			sources = append(sources, src)
 		}
 	}
 
-	return data.Sources, nil
+	return sources, nil
 }
 
 // getArticles retrieves articles by the provided path and parameters.
 // The uint return value indicates the number of available articles. The
 // length of the returned slice may be less than this value; additional calls
 // need to be make to retrieve other available articles.
-func (c *Client) getArticles(ctx context.Context, endpoint string, pr params) ([]Article, uint, error) {
-	statusCode, body, err := c.get(
-		ctx,
-		endpoint,
-		pr,
-	)
-	if err != nil {
+func (c *Client) getArticles(ctx context.Context, endpoint Endpoint, pr params) ([]Article, uint, error) {
+	var res ArticlesResult
+
+	if err := c.exchange(ctx, endpoint, pr, &res); err != nil {
 		return nil, 0, err
 	}
-	defer body.Close()
+
+	return res.Articles, res.TotalResults, nil
+}
+
+// exchange validates the parameters, builds a GET request to the
+// provided endpoint and passes it through the middleware chain. The
+// response is decoded into the result.
+func (c *Client) exchange(ctx context.Context, endpoint Endpoint, pr params, result interface{}) error {
+	if err := pr.Validate(); err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(ctx, endpoint, pr.rawQuery())
+	if err != nil {
+		return err
+	}
+
+	return c.handler()(&Exchange{
+		Endpoint: endpoint,
+		Params:   paramsValue(pr),
+		Request:  req,
+		Result:   result,
+	})
+}
+
+// roundTrip sends the exchange request and decodes the response into the
+// exchange result. It is the innermost handler of the middleware chain.
+func (c *Client) roundTrip(ex *Exchange) error {
+	resp, err := c.get(ex.Endpoint, ex.Request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ex.Response = resp
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
 
 	data := struct {
-		Status       string    `json:"status"`
-		Code         string    `json:"code"`
-		TotalResults uint      `json:"totalResults"`
-		Articles     []Article `json:"articles"`
-		Message      string    `json:"message"`
+		Status  string  `json:"status"`
+		Code    APICode `json:"code"`
+		Message string  `json:"message"`
 	}{}
 
-	if err = json.NewDecoder(body).Decode(&data); err != nil {
-		return nil, 0, err
+	if err = json.Unmarshal(body, &data); err != nil {
+		return err
 	}
 
 	if data.Status != "ok" {
-		return nil, 0, &Error{
-			HTTPCode: statusCode,
-			APICode:  data.Code,
-			Message:  data.Message,
-		}
+		return newError(resp, data.Code, data.Message)
 	}
 
-	return data.Articles, data.TotalResults, nil
+	return json.Unmarshal(body, ex.Result)
 }
 
-// get sends a GET request to the provided endpoint.
-func (c *Client) get(ctx context.Context, endpoint string, pr params) (int, io.ReadCloser, error) {
-	if err := pr.validate(); err != nil {
-		return 0, nil, err
+// get sends the GET request to the provided endpoint, serving it from
+// the cache if caching is enabled. The caller is responsible for closing
+// the response body.
+func (c *Client) get(endpoint Endpoint, req *http.Request) (*http.Response, error) {
+	if c.cache != nil && c.cache.store != nil {
+		return c.getCached(endpoint, req)
 	}
 
+	return c.do(req)
+}
+
+// newRequest creates a GET request with the provided raw query to the
+// endpoint.
+func (c *Client) newRequest(ctx context.Context, endpoint Endpoint, rawQuery string) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodGet,
-		fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, pr.rawQuery()),
+		fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, rawQuery),
 		http.NoBody,
 	)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	req.Header.Set("X-Api-Key", c.apiKey)
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return 0, nil, err
+	return req, nil
+}
+
+// do sends the request, retrying it if retry policy is set.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.retry == nil {
+		return c.send(req)
+	}
+
+	return c.retry.do(req, c.send)
+}
+
+// send sends a single request once it is allowed by the rate limit and
+// the daily budget.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.budget != nil {
+		if err := c.budget.take(req.Context()); err != nil {
+			return nil, err
+		}
 	}
 
-	return resp.StatusCode, resp.Body, nil
+	return c.client.Do(req)
 }
 
 // params is an interface is used to process query parameters.
 type params interface {
-	// validate should validate the params.
-	validate() error
+	// Validate should validate the params.
+	Validate() error
 
 	// rawQuery should build a raw query from the params.
 	rawQuery() string
@@ -1,17 +1,15 @@
 package newsapi
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"io"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 )
 
 func Test_WithHTTPClient(t *testing.T) {
@@ -51,7 +49,9 @@ func Test_Client_Everything(t *testing.T) {
 		"Invalid parameters": {
 			Param: EverythingParams{},
 			Resp:  httpmock.NewStringResponder(http.StatusOK, ""),
-			Err:   ErrParamsScopeTooBroad,
+			Err: &ValidationError{Fields: []FieldError{
+				{Err: ErrParamsScopeTooBroad},
+			}},
 		},
 		"Newsapi returned invalid JSON": {
 			Param: EverythingParams{
@@ -211,7 +211,9 @@ func Test_Client_TopHeadlines(t *testing.T) {
 		"Invalid parameters": {
 			Param: TopHeadlinesParams{},
 			Resp:  httpmock.NewStringResponder(http.StatusOK, ""),
-			Err:   ErrParamsScopeTooBroad,
+			Err: &ValidationError{Fields: []FieldError{
+				{Err: ErrParamsScopeTooBroad},
+			}},
 		},
 		"Newsapi returned invalid JSON": {
 			Param: TopHeadlinesParams{
@@ -372,7 +374,9 @@ func Test_Client_Sources(t *testing.T) {
 				},
 			},
 			Resp: httpmock.NewStringResponder(http.StatusOK, ""),
-			Err:  ErrInvalidCategory,
+			Err: &ValidationError{Fields: []FieldError{
+				{Field: "Categories", Value: Category("test"), Err: ErrInvalidCategory},
+			}},
 		},
 		"Newsapi returned invalid JSON": {
 			Param: SourceParams{
@@ -473,6 +477,73 @@ func Test_Client_Sources(t *testing.T) {
 				},
 			},
 		},
+		"Multiple filter values": {
+			Param: SourceParams{
+				Categories: []Category{
+					CategoryBusiness,
+					CategoryScience,
+				},
+				Languages: []Language{
+					LanguageEnglish,
+				},
+			},
+			Resp: func(req *http.Request) (*http.Response, error) {
+				q := req.URL.Query()
+				assert.Len(t, q["category"], 1)
+				assert.Equal(t, []string{"en"}, q["language"])
+
+				return httpmock.NewStringResponse(
+					http.StatusOK,
+					fmt.Sprintf(`{
+						"status":"ok",
+						"sources":[
+							{"id": "%[1]s", "category": "%[1]s"},
+							{"id": "both"}
+						]
+					}`, q.Get("category")),
+				), nil
+			},
+			Sources: []Source{
+				{SourceID: SourceID{ID: "business"}, Category: CategoryBusiness},
+				{SourceID: SourceID{ID: "both"}},
+				{SourceID: SourceID{ID: "science"}, Category: CategoryScience},
+			},
+		},
+		"Invalid parameters with multiple filter values": {
+			Param: SourceParams{
+				Categories: []Category{
+					CategoryBusiness,
+					"test",
+				},
+			},
+			Resp: httpmock.NewStringResponder(http.StatusOK, ""),
+			Err: &ValidationError{Fields: []FieldError{
+				{Field: "Categories", Value: Category("test"), Err: ErrInvalidCategory},
+			}},
+		},
+		"Newsapi returned an error for one of filter values": {
+			Param: SourceParams{
+				Countries: []Country{
+					CountryArgentina,
+					CountryAustria,
+				},
+			},
+			Resp: func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("country") == "at" {
+					return httpmock.NewStringResponse(
+						http.StatusBadRequest,
+						`{"status":"error","code":"100","message":"bad thing"}`,
+					), nil
+				}
+
+				return httpmock.NewStringResponse(http.StatusOK, `{"status":"ok","sources":[]}`), nil
+			},
+			Err: &Error{
+				HTTPCode: http.StatusBadRequest,
+				APICode:  "100",
+				Message:  "bad thing",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -508,19 +579,21 @@ func Test_Client_Sources(t *testing.T) {
 	}
 }
 
-func Test_Client_get(t *testing.T) {
+func Test_Client_exchange(t *testing.T) {
 	tests := map[string]struct {
-		StatusCode int
-		Body       []byte
 		Params     params
 		Resp       httpmock.Responder
 		NilContext bool
+		Result     interface{}
+		StatusCode int
 		Err        error
 	}{
 		"Validate returns an error": {
 			Params: &EverythingParams{},
 			Resp:   httpmock.NewBytesResponder(http.StatusBadRequest, []byte{1, 2, 3, 4}),
-			Err:    ErrParamsScopeTooBroad,
+			Err: &ValidationError{Fields: []FieldError{
+				{Err: ErrParamsScopeTooBroad},
+			}},
 		},
 		"Invalid context": {
 			Params:     &SourceParams{},
@@ -535,17 +608,25 @@ func Test_Client_get(t *testing.T) {
 			},
 			Err: assert.AnError,
 		},
-		"Successful request": {
-			StatusCode: http.StatusBadRequest,
-			Body:       []byte{1, 2, 3, 4},
+		"Invalid JSON": {
 			Params:     &SourceParams{},
+			Resp:       httpmock.NewBytesResponder(http.StatusBadRequest, []byte{1, 2, 3, 4}),
+			StatusCode: http.StatusBadRequest,
+			Err:        assert.AnError,
+		},
+		"Successful request": {
+			Params: &SourceParams{},
 			Resp: func(req *http.Request) (*http.Response, error) {
 				assert.Equal(t, "777", req.Header.Get("X-Api-Key"))
-				return httpmock.NewBytesResponse(
-					http.StatusBadRequest,
-					[]byte{1, 2, 3, 4},
+				return httpmock.NewStringResponse(
+					http.StatusOK,
+					`{"status":"ok","sources":[{"id":"bbc"}]}`,
 				), nil
 			},
+			Result: &SourcesResult{Sources: []Source{
+				{SourceID: SourceID{ID: "bbc"}},
+			}},
+			StatusCode: http.StatusOK,
 		},
 	}
 
@@ -571,15 +652,24 @@ func Test_Client_get(t *testing.T) {
 				ctx = context.Background()
 			}
 
-			statusCode, body, err := client.get(
-				ctx,
-				"123",
-				test.Params,
+			var (
+				res        SourcesResult
+				statusCode int
 			)
 
-			if body != nil {
-				defer body.Close()
-			}
+			client.mws = []Middleware{func(next Handler) Handler {
+				return func(ex *Exchange) error {
+					err := next(ex)
+					if ex.Response != nil {
+						statusCode = ex.Response.StatusCode
+					}
+
+					return err
+				}
+			}}
+
+			err := client.exchange(ctx, "123", test.Params, &res)
+			assert.Equal(t, test.StatusCode, statusCode)
 
 			if errors.Is(test.Err, assert.AnError) {
 				assert.Error(t, err)
@@ -591,12 +681,7 @@ func Test_Client_get(t *testing.T) {
 				return
 			}
 
-			buf := &bytes.Buffer{}
-			_, err = io.Copy(buf, body)
-			require.NoError(t, err)
-
-			assert.Equal(t, test.StatusCode, statusCode)
-			assert.Equal(t, test.Body, buf.Bytes())
+			assert.Equal(t, test.Result, &res)
 		})
 	}
 }
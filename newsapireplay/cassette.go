@@ -0,0 +1,105 @@
+package newsapireplay
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+)
+
+// _redacted replaces values of sensitive headers in cassettes.
+const _redacted = "REDACTED"
+
+// _sensitiveHeaders are request headers whose values are never written
+// to cassettes.
+var _sensitiveHeaders = []string{
+	"X-Api-Key",
+	"Authorization",
+}
+
+// Cassette contains recorded interactions.
+type Cassette struct {
+	// Interactions specifies the recorded interactions, in the order
+	// they were recorded.
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and response pair.
+type Interaction struct {
+	// Request specifies the recorded request.
+	Request Request `json:"request"`
+
+	// Response specifies the recorded response.
+	Response Response `json:"response"`
+}
+
+// Request contains recorded request information.
+type Request struct {
+	// Method specifies the request method.
+	Method string `json:"method"`
+
+	// Endpoint specifies the requested endpoint, e.g. everything.
+	Endpoint string `json:"endpoint"`
+
+	// Query specifies the canonical raw query of the request, with keys
+	// sorted and the api key removed.
+	Query string `json:"query"`
+
+	// Header specifies the request headers, with sensitive values
+	// redacted.
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Response contains recorded response information.
+type Response struct {
+	// StatusCode specifies the response status code.
+	StatusCode int `json:"statusCode"`
+
+	// Header specifies the response headers.
+	Header http.Header `json:"header,omitempty"`
+
+	// Body specifies the response body.
+	Body string `json:"body"`
+}
+
+// LoadCassette reads the cassette from the file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to the file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// redactHeader returns a copy of the header with sensitive values
+// redacted.
+func redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	res := h.Clone()
+
+	for _, name := range _sensitiveHeaders {
+		if res.Get(name) != "" {
+			res.Set(name, _redacted)
+		}
+	}
+
+	return res
+}
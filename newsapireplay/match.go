@@ -0,0 +1,199 @@
+package newsapireplay
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+// _endpoints are newsapi endpoints, longest first, so that an endpoint
+// that is a suffix of another one is not matched by mistake.
+var _endpoints = []newsapi.Endpoint{
+	newsapi.EndpointSources,
+	newsapi.EndpointTopHeadlines,
+	newsapi.EndpointEverything,
+}
+
+// newRequest creates a recorded request from the http request.
+func newRequest(req *http.Request) Request {
+	return Request{
+		Method:   req.Method,
+		Endpoint: endpoint(req.URL.Path),
+		Query:    canonicalQuery(req.URL.Query(), nil),
+		Header:   redactHeader(req.Header),
+	}
+}
+
+// endpoint returns the newsapi endpoint of the url path. Paths that do
+// not end with a known endpoint are returned without the leading slash.
+func endpoint(path string) string {
+	for _, e := range _endpoints {
+		if path == string(e) || strings.HasSuffix(path, "/"+string(e)) {
+			return string(e)
+		}
+	}
+
+	return strings.TrimPrefix(path, "/")
+}
+
+// canonicalQuery encodes the query with keys sorted, the same way
+// newsapi client builds raw queries. The api key and ignored parameters
+// are removed.
+func canonicalQuery(q url.Values, ignored []string) string {
+	res := make(url.Values, len(q))
+
+	for key, values := range q {
+		if key == "apiKey" || contains(ignored, key) {
+			continue
+		}
+
+		res[key] = values
+	}
+
+	return res.Encode()
+}
+
+// match checks if the recorded request matches the requested one. The
+// ignored query parameters are not compared.
+func match(recorded, requested Request, ignored []string) bool {
+	return recorded.Method == requested.Method &&
+		recorded.Endpoint == requested.Endpoint &&
+		filterQuery(recorded.Query, ignored) == filterQuery(requested.Query, ignored)
+}
+
+// filterQuery removes the ignored parameters from the canonical query.
+func filterQuery(rawQuery string, ignored []string) string {
+	if len(ignored) == 0 {
+		return rawQuery
+	}
+
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	return canonicalQuery(q, ignored)
+}
+
+// closest returns the index of the recorded request that differs from
+// the requested one the least, or -1 if there are no recorded requests.
+func closest(interactions []Interaction, requested Request) int {
+	best, bestScore := -1, 0
+
+	for i, in := range interactions {
+		score := similarity(in.Request, requested)
+		if best == -1 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	return best
+}
+
+// similarity scores how similar the requests are. Matching method and
+// endpoint outweigh any number of matching query parameters.
+func similarity(a, b Request) int {
+	var score int
+
+	if a.Method == b.Method {
+		score += 2000
+	}
+
+	if a.Endpoint == b.Endpoint {
+		score += 1000
+	}
+
+	qa, _ := url.ParseQuery(a.Query)
+	qb, _ := url.ParseQuery(b.Query)
+
+	for _, key := range queryKeys(qa, qb) {
+		if strings.Join(qa[key], ",") == strings.Join(qb[key], ",") {
+			score++
+		} else {
+			score--
+		}
+	}
+
+	return score
+}
+
+// diff describes the differences between the recorded and the requested
+// requests, one line per request line or query parameter. Lines only
+// present in the recorded request are prefixed with "-", lines only
+// present in the requested one with "+".
+func diff(recorded, requested Request) string {
+	var b strings.Builder
+
+	b.WriteString("--- recorded\n+++ requested\n")
+
+	line := func(recorded, requested string) {
+		if recorded == requested {
+			fmt.Fprintf(&b, "  %s\n", recorded)
+			return
+		}
+
+		if recorded != "" {
+			fmt.Fprintf(&b, "- %s\n", recorded)
+		}
+
+		if requested != "" {
+			fmt.Fprintf(&b, "+ %s\n", requested)
+		}
+	}
+
+	line(recorded.Method+" "+recorded.Endpoint, requested.Method+" "+requested.Endpoint)
+
+	qa, _ := url.ParseQuery(recorded.Query)
+	qb, _ := url.ParseQuery(requested.Query)
+
+	for _, key := range queryKeys(qa, qb) {
+		line(queryLine(qa, key), queryLine(qb, key))
+	}
+
+	return b.String()
+}
+
+// queryLine formats the query parameter, or returns an empty string if
+// it is not present.
+func queryLine(q url.Values, key string) string {
+	values, ok := q[key]
+	if !ok {
+		return ""
+	}
+
+	return key + "=" + strings.Join(values, ",")
+}
+
+// queryKeys returns sorted keys of both queries.
+func queryKeys(a, b url.Values) []string {
+	keys := make([]string, 0, len(a)+len(b))
+
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// contains checks if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package newsapireplay
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://newsapi.org/v2/everything?sources=bbc&q=bitcoin&apiKey=secret", http.NoBody)
+	assert.NoError(t, err)
+
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("Authorization", "secret")
+	req.Header.Set("X-Test", "test")
+
+	assert.Equal(t, Request{
+		Method:   http.MethodGet,
+		Endpoint: "everything",
+		Query:    "q=bitcoin&sources=bbc",
+		Header: http.Header{
+			"X-Api-Key":     {_redacted},
+			"Authorization": {_redacted},
+			"X-Test":        {"test"},
+		},
+	}, newRequest(req))
+	assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+}
+
+func Test_endpoint(t *testing.T) {
+	assert.Equal(t, "everything", endpoint("/v2/everything"))
+	assert.Equal(t, "top-headlines", endpoint("/top-headlines"))
+	assert.Equal(t, "top-headlines/sources", endpoint("/v2/top-headlines/sources"))
+	assert.Equal(t, "sources", endpoint("sources"))
+	assert.Equal(t, "v2/other", endpoint("/v2/other"))
+}
+
+func Test_canonicalQuery(t *testing.T) {
+	q := url.Values{
+		"to":     {"2022-02-22"},
+		"q":      {"bitcoin"},
+		"apiKey": {"secret"},
+		"from":   {"2022-02-21"},
+	}
+
+	assert.Equal(t, "from=2022-02-21&q=bitcoin&to=2022-02-22", canonicalQuery(q, nil))
+	assert.Equal(t, "q=bitcoin", canonicalQuery(q, []string{"from", "to"}))
+}
+
+func Test_match(t *testing.T) {
+	a := Request{Method: "GET", Endpoint: "everything", Query: "from=1&q=bitcoin"}
+
+	assert.True(t, match(a, a, nil))
+	assert.False(t, match(a, Request{Method: "GET", Endpoint: "everything", Query: "from=2&q=bitcoin"}, nil))
+	assert.True(t, match(a, Request{Method: "GET", Endpoint: "everything", Query: "from=2&q=bitcoin"}, []string{"from"}))
+	assert.True(t, match(a, Request{Method: "GET", Endpoint: "everything", Query: "q=bitcoin"}, []string{"from"}))
+	assert.False(t, match(a, Request{Method: "GET", Endpoint: "top-headlines", Query: "from=1&q=bitcoin"}, nil))
+	assert.False(t, match(a, Request{Method: "POST", Endpoint: "everything", Query: "from=1&q=bitcoin"}, nil))
+}
+
+func Test_closest(t *testing.T) {
+	interactions := []Interaction{
+		{Request: Request{Method: "GET", Endpoint: "top-headlines", Query: "q=bitcoin"}},
+		{Request: Request{Method: "GET", Endpoint: "everything", Query: "language=en&q=ethereum"}},
+		{Request: Request{Method: "GET", Endpoint: "everything", Query: "language=de&q=bitcoin&sortBy=popularity"}},
+	}
+
+	assert.Equal(t, 2, closest(interactions, Request{Method: "GET", Endpoint: "everything", Query: "language=en&q=bitcoin&sortBy=popularity"}))
+	assert.Equal(t, 0, closest(interactions, Request{Method: "GET", Endpoint: "top-headlines"}))
+	assert.Equal(t, -1, closest(nil, Request{}))
+}
+
+func Test_diff(t *testing.T) {
+	assert.Equal(
+		t,
+		"--- recorded\n+++ requested\n"+
+			"- GET top-headlines\n"+
+			"+ GET everything\n"+
+			"- country=us\n"+
+			"  q=bitcoin\n"+
+			"+ sources=bbc,wired\n",
+		diff(
+			Request{Method: "GET", Endpoint: "top-headlines", Query: "country=us&q=bitcoin"},
+			Request{Method: "GET", Endpoint: "everything", Query: "q=bitcoin&sources=bbc&sources=wired"},
+		),
+	)
+}
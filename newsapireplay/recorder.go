@@ -0,0 +1,277 @@
+// Package newsapireplay records newsapi traffic into cassette files and
+// replays it, so tests can run deterministically without network access.
+//
+// Recorder is an http.RoundTripper, so it can be plugged into the client
+// using newsapi.WithHTTPClient:
+//
+//	rec, err := newsapireplay.NewRecorder("testdata/bitcoin.json", newsapireplay.ModeReplayStrict)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	client := newsapi.NewClient("apiKey", newsapi.WithHTTPClient(rec.Client()))
+package newsapireplay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// All available recorder modes.
+const (
+	// ModeRecord sends requests using the underlying transport and
+	// records them. The cassette is written by Save method.
+	ModeRecord Mode = iota + 1
+
+	// ModeReplayStrict replays recorded interactions without sending
+	// requests. A request matches an interaction only if their method,
+	// endpoint and canonical query are equal. Every interaction is
+	// replayed at most once, in the order of recording.
+	ModeReplayStrict
+
+	// ModeReplayLenient replays recorded interactions without sending
+	// requests. Ignored query parameters are not compared and
+	// interactions can be replayed any number of times.
+	ModeReplayLenient
+)
+
+// Mode determines whether the recorder records or replays interactions.
+type Mode int
+
+// Option is used to set recorder configuration options.
+type Option func(r *Recorder)
+
+// WithTransport sets the transport used to send requests in record mode.
+// By default, http.DefaultTransport is used.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithIgnoredParams sets query parameters that are not compared in
+// lenient replay mode, e.g. from and to.
+func WithIgnoredParams(params ...string) Option {
+	return func(r *Recorder) {
+		r.ignored = append(r.ignored, params...)
+	}
+}
+
+// MismatchError is returned whenever no recorded interaction matches the
+// request in replay mode.
+type MismatchError struct {
+	// Request specifies the unmatched request.
+	Request Request
+
+	// Closest specifies the recorded request that differs from the
+	// unmatched one the least. It is nil if the cassette is empty.
+	Closest *Request
+
+	// Diff specifies the differences between the closest recorded
+	// request and the unmatched one.
+	Diff string
+
+	// Exhausted specifies whether matching interactions exist, but all
+	// of them have already been replayed in strict mode.
+	Exhausted bool
+}
+
+// Error implements error interface and returns formatted error message.
+func (e *MismatchError) Error() string {
+	msg := fmt.Sprintf("newsapireplay: no recorded interaction matches %s %s?%s", e.Request.Method, e.Request.Endpoint, e.Request.Query)
+	if e.Exhausted {
+		return msg + ", all matching interactions have already been replayed"
+	}
+
+	if e.Closest == nil {
+		return msg + ", cassette is empty"
+	}
+
+	return msg + ", closest recorded interaction:\n" + e.Diff
+}
+
+// Recorder records and replays newsapi interactions.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+	ignored   []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// NewRecorder creates a fresh instance of recorder. In replay modes the
+// cassette is loaded from the file; in record mode the file is
+// overwritten once Save method is called.
+func NewRecorder(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: http.DefaultTransport,
+		cassette:  &Cassette{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+	case ModeReplayStrict, ModeReplayLenient:
+		c, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+
+		r.cassette = c
+		r.replayed = make([]bool, len(c.Interactions))
+	default:
+		return nil, errors.New("newsapireplay: invalid mode")
+	}
+
+	return r, nil
+}
+
+// Client returns an http client that sends requests through the
+// recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{
+		Transport: r,
+	}
+}
+
+// RoundTrip records or replays the request, depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+
+	return r.replay(req)
+}
+
+// Save writes recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// Unused returns recorded requests that have not been replayed yet. It
+// can be used in strict replay mode to check that all expected requests
+// were sent.
+func (r *Recorder) Unused() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var res []Request
+
+	for i, ok := range r.replayed {
+		if !ok {
+			res = append(res, r.cassette.Interactions[i].Request)
+		}
+	}
+
+	return res
+}
+
+// record sends the request and records the interaction.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: newRequest(req),
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(body),
+		},
+	})
+
+	return resp, nil
+}
+
+// replay finds the interaction matching the request and returns its
+// response.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	requested := newRequest(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ignored []string
+	if r.mode == ModeReplayLenient {
+		ignored = r.ignored
+	}
+
+	for i, in := range r.cassette.Interactions {
+		if r.mode == ModeReplayStrict && r.replayed[i] {
+			continue
+		}
+
+		if match(in.Request, requested, ignored) {
+			r.replayed[i] = true
+			return newResponse(req, in.Response), nil
+		}
+	}
+
+	err := &MismatchError{
+		Request: requested,
+	}
+
+	for _, in := range r.cassette.Interactions {
+		if match(in.Request, requested, nil) {
+			err.Exhausted = true
+			return nil, err
+		}
+	}
+
+	if i := closest(r.cassette.Interactions, requested); i >= 0 {
+		closest := r.cassette.Interactions[i].Request
+		err.Closest = &closest
+		err.Diff = diff(closest, requested)
+	}
+
+	return nil, err
+}
+
+// newResponse creates an http response from the recorded response.
+func newResponse(req *http.Request, resp Response) *http.Response {
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(resp.Body))),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,156 @@
+package newsapireplay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+	"github.com/jellydator/newsapi-go/newsapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordCassette(t *testing.T) string {
+	t.Helper()
+
+	srv := newsapitest.NewServer(
+		newsapitest.WithAPIKey("secret"),
+		newsapitest.WithSources(newsapi.Source{
+			SourceID: newsapi.SourceID{ID: "bbc", Name: "BBC"},
+			Language: newsapi.LanguageEnglish,
+			Country:  newsapi.CountryUnitedKingdom,
+		}),
+		newsapitest.WithArticles(newsapi.Article{
+			Source:      newsapi.SourceID{ID: "bbc", Name: "BBC"},
+			Title:       "Bitcoin price falls",
+			URL:         "https://www.bbc.co.uk/news/1",
+			PublishedAt: time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC),
+		}),
+	)
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := NewRecorder(path, ModeRecord)
+	require.NoError(t, err)
+
+	client := newsapi.NewClient("secret", newsapi.WithBaseURL(srv.URL+"/"), newsapi.WithHTTPClient(rec.Client()))
+
+	_, _, err = client.Everything(context.Background(), newsapi.EverythingParams{Query: "bitcoin", Language: newsapi.LanguageEnglish})
+	require.NoError(t, err)
+
+	_, err = client.Sources(context.Background(), newsapi.SourceParams{})
+	require.NoError(t, err)
+
+	_, _, err = client.TopHeadlines(context.Background(), newsapi.TopHeadlinesParams{})
+	require.Error(t, err)
+
+	require.NoError(t, rec.Save())
+
+	return path
+}
+
+func Test_Recorder_record(t *testing.T) {
+	path := recordCassette(t)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	c, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, c.Interactions, 2)
+
+	in := c.Interactions[0]
+	assert.Equal(t, "GET", in.Request.Method)
+	assert.Equal(t, "everything", in.Request.Endpoint)
+	assert.Equal(t, "language=en&q=bitcoin", in.Request.Query)
+	assert.Equal(t, _redacted, in.Request.Header.Get("X-Api-Key"))
+	assert.Equal(t, 200, in.Response.StatusCode)
+	assert.Contains(t, in.Response.Body, "Bitcoin price falls")
+
+	assert.Equal(t, "top-headlines/sources", c.Interactions[1].Request.Endpoint)
+}
+
+func Test_Recorder_replayStrict(t *testing.T) {
+	path := recordCassette(t)
+
+	rec, err := NewRecorder(path, ModeReplayStrict)
+	require.NoError(t, err)
+
+	client := newsapi.NewClient("other", newsapi.WithBaseURL("http://replay.invalid/v2/"), newsapi.WithHTTPClient(rec.Client()))
+
+	assert.Len(t, rec.Unused(), 2)
+
+	articles, total, err := client.Everything(context.Background(), newsapi.EverythingParams{Language: newsapi.LanguageEnglish, Query: "bitcoin"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), total)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "Bitcoin price falls", articles[0].Title)
+
+	assert.Equal(t, []Request{{
+		Method:   "GET",
+		Endpoint: "top-headlines/sources",
+		Header:   map[string][]string{"X-Api-Key": {_redacted}},
+	}}, rec.Unused())
+
+	_, _, err = client.Everything(context.Background(), newsapi.EverythingParams{Language: newsapi.LanguageEnglish, Query: "bitcoin"})
+
+	var merr *MismatchError
+	require.True(t, errors.As(err, &merr))
+	assert.True(t, merr.Exhausted)
+	assert.Contains(t, err.Error(), "all matching interactions have already been replayed")
+
+	_, _, err = client.Everything(context.Background(), newsapi.EverythingParams{Language: newsapi.LanguageGerman, Query: "bitcoin"})
+	require.True(t, errors.As(err, &merr))
+	assert.False(t, merr.Exhausted)
+	require.NotNil(t, merr.Closest)
+	assert.Equal(t, "language=en&q=bitcoin", merr.Closest.Query)
+	assert.Equal(t, "--- recorded\n+++ requested\n  GET everything\n- language=en\n+ language=de\n  q=bitcoin\n", merr.Diff)
+	assert.Contains(t, err.Error(), "no recorded interaction matches GET everything?language=de&q=bitcoin, closest recorded interaction:\n")
+}
+
+func Test_Recorder_replayLenient(t *testing.T) {
+	path := recordCassette(t)
+
+	rec, err := NewRecorder(path, ModeReplayLenient, WithIgnoredParams("language"))
+	require.NoError(t, err)
+
+	client := newsapi.NewClient("other", newsapi.WithHTTPClient(rec.Client()))
+
+	for _, language := range []newsapi.Language{newsapi.LanguageEnglish, newsapi.LanguageGerman, ""} {
+		articles, _, err := client.Everything(context.Background(), newsapi.EverythingParams{Query: "bitcoin", Language: language})
+		require.NoError(t, err)
+		assert.Len(t, articles, 1)
+	}
+
+	_, _, err = client.Everything(context.Background(), newsapi.EverythingParams{Query: "ethereum"})
+	assert.True(t, errors.As(err, new(*MismatchError)))
+}
+
+func Test_NewRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	_, err := NewRecorder(path, ModeReplayStrict)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = NewRecorder(path, Mode(0))
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err = NewRecorder(path, ModeReplayLenient)
+	assert.Error(t, err)
+
+	require.NoError(t, (&Cassette{}).Save(path))
+
+	rec, err := NewRecorder(path, ModeReplayStrict)
+	require.NoError(t, err)
+
+	_, err = newsapi.NewClient("", newsapi.WithHTTPClient(rec.Client())).Sources(context.Background(), newsapi.SourceParams{})
+	assert.Contains(t, err.Error(), "no recorded interaction matches GET top-headlines/sources?, cassette is empty")
+}
@@ -0,0 +1,57 @@
+package newsapitest
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+// Failure writes a failed response instead of the regular one.
+type Failure func(w http.ResponseWriter)
+
+// APIError returns a failure that responds with newsapi error payload.
+func APIError(status int, code newsapi.APICode, message string) Failure {
+	return func(w http.ResponseWriter) {
+		writeError(w, status, code, message)
+	}
+}
+
+// RateLimited returns a failure that responds with rateLimited error,
+// advising to retry after the provided duration. Zero duration omits
+// Retry-After header.
+func RateLimited(retryAfter time.Duration) Failure {
+	return func(w http.ResponseWriter) {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		}
+
+		writeError(
+			w,
+			http.StatusTooManyRequests,
+			newsapi.APICodeRateLimited,
+			"You have made too many requests recently.",
+		)
+	}
+}
+
+// ServerError returns a failure that responds with unexpectedError error.
+func ServerError() Failure {
+	return APIError(
+		http.StatusInternalServerError,
+		newsapi.APICodeUnexpectedError,
+		"This shouldn't happen, and if it does then it's our fault, not yours.",
+	)
+}
+
+// MalformedJSON returns a failure that responds with a truncated JSON
+// payload.
+func MalformedJSON() Failure {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"status":"ok","totalResults":`)
+	}
+}
@@ -0,0 +1,140 @@
+package newsapitest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+var _ newsapi.API = (*FakeAPI)(nil)
+
+// Call contains information about a call received by the fake API.
+type Call struct {
+	// Endpoint specifies the endpoint corresponding to the called
+	// method.
+	Endpoint newsapi.Endpoint
+
+	// Params specifies the params the method was called with. It is one
+	// of newsapi.EverythingParams, newsapi.TopHeadlinesParams and
+	// newsapi.SourceParams.
+	Params interface{}
+}
+
+// FakeAPI is an in-memory newsapi.API implementation that records the
+// params it was called with. Results are produced by the function fields;
+// methods whose function is not set return no results. The zero value is
+// ready to use.
+type FakeAPI struct {
+	// EverythingFunc is called by Everything method.
+	EverythingFunc func(ctx context.Context, pr newsapi.EverythingParams) ([]newsapi.Article, uint, error)
+
+	// TopHeadlinesFunc is called by TopHeadlines method.
+	TopHeadlinesFunc func(ctx context.Context, pr newsapi.TopHeadlinesParams) ([]newsapi.Article, uint, error)
+
+	// SourcesFunc is called by Sources method.
+	SourcesFunc func(ctx context.Context, pr newsapi.SourceParams) ([]newsapi.Source, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Everything records the call and calls EverythingFunc.
+func (f *FakeAPI) Everything(ctx context.Context, pr newsapi.EverythingParams) ([]newsapi.Article, uint, error) {
+	f.record(newsapi.EndpointEverything, pr)
+
+	if f.EverythingFunc == nil {
+		return nil, 0, nil
+	}
+
+	return f.EverythingFunc(ctx, pr)
+}
+
+// TopHeadlines records the call and calls TopHeadlinesFunc.
+func (f *FakeAPI) TopHeadlines(ctx context.Context, pr newsapi.TopHeadlinesParams) ([]newsapi.Article, uint, error) {
+	f.record(newsapi.EndpointTopHeadlines, pr)
+
+	if f.TopHeadlinesFunc == nil {
+		return nil, 0, nil
+	}
+
+	return f.TopHeadlinesFunc(ctx, pr)
+}
+
+// Sources records the call and calls SourcesFunc.
+func (f *FakeAPI) Sources(ctx context.Context, pr newsapi.SourceParams) ([]newsapi.Source, error) {
+	f.record(newsapi.EndpointSources, pr)
+
+	if f.SourcesFunc == nil {
+		return nil, nil
+	}
+
+	return f.SourcesFunc(ctx, pr)
+}
+
+// Calls returns all calls received so far, in the order they were
+// received.
+func (f *FakeAPI) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// EverythingCalls returns params of all Everything calls.
+func (f *FakeAPI) EverythingCalls() []newsapi.EverythingParams {
+	var res []newsapi.EverythingParams
+
+	for _, call := range f.Calls() {
+		if pr, ok := call.Params.(newsapi.EverythingParams); ok {
+			res = append(res, pr)
+		}
+	}
+
+	return res
+}
+
+// TopHeadlinesCalls returns params of all TopHeadlines calls.
+func (f *FakeAPI) TopHeadlinesCalls() []newsapi.TopHeadlinesParams {
+	var res []newsapi.TopHeadlinesParams
+
+	for _, call := range f.Calls() {
+		if pr, ok := call.Params.(newsapi.TopHeadlinesParams); ok {
+			res = append(res, pr)
+		}
+	}
+
+	return res
+}
+
+// SourcesCalls returns params of all Sources calls.
+func (f *FakeAPI) SourcesCalls() []newsapi.SourceParams {
+	var res []newsapi.SourceParams
+
+	for _, call := range f.Calls() {
+		if pr, ok := call.Params.(newsapi.SourceParams); ok {
+			res = append(res, pr)
+		}
+	}
+
+	return res
+}
+
+// Reset forgets all recorded calls.
+func (f *FakeAPI) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+// record records the call.
+func (f *FakeAPI) record(endpoint newsapi.Endpoint, pr interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{
+		Endpoint: endpoint,
+		Params:   pr,
+	})
+}
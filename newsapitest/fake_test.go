@@ -0,0 +1,51 @@
+package newsapitest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jellydator/newsapi-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FakeAPI(t *testing.T) {
+	fake := &FakeAPI{
+		EverythingFunc: func(_ context.Context, pr newsapi.EverythingParams) ([]newsapi.Article, uint, error) {
+			return []newsapi.Article{{Title: pr.Query}}, 1, nil
+		},
+		SourcesFunc: func(_ context.Context, _ newsapi.SourceParams) ([]newsapi.Source, error) {
+			return nil, assert.AnError
+		},
+	}
+
+	api := newsapi.Decorate(fake)
+
+	articles, total, err := api.Everything(context.Background(), newsapi.EverythingParams{Query: "bitcoin"})
+	require.NoError(t, err)
+	assert.Equal(t, []newsapi.Article{{Title: "bitcoin"}}, articles)
+	assert.Equal(t, uint(1), total)
+
+	articles, total, err = api.TopHeadlines(context.Background(), newsapi.TopHeadlinesParams{Country: newsapi.CountryLatvia})
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+	assert.Zero(t, total)
+
+	_, err = api.Sources(context.Background(), newsapi.SourceParams{Languages: []newsapi.Language{newsapi.LanguageEnglish}})
+	assert.Equal(t, assert.AnError, err)
+
+	assert.Equal(t, []Call{
+		{Endpoint: newsapi.EndpointEverything, Params: newsapi.EverythingParams{Query: "bitcoin"}},
+		{Endpoint: newsapi.EndpointTopHeadlines, Params: newsapi.TopHeadlinesParams{Country: newsapi.CountryLatvia}},
+		{Endpoint: newsapi.EndpointSources, Params: newsapi.SourceParams{Languages: []newsapi.Language{newsapi.LanguageEnglish}}},
+	}, fake.Calls())
+	assert.Equal(t, []newsapi.EverythingParams{{Query: "bitcoin"}}, fake.EverythingCalls())
+	assert.Equal(t, []newsapi.TopHeadlinesParams{{Country: newsapi.CountryLatvia}}, fake.TopHeadlinesCalls())
+	assert.Equal(t, []newsapi.SourceParams{{Languages: []newsapi.Language{newsapi.LanguageEnglish}}}, fake.SourcesCalls())
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+
+	_, err = (&FakeAPI{}).Sources(context.Background(), newsapi.SourceParams{})
+	assert.NoError(t, err)
+}
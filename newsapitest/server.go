@@ -0,0 +1,562 @@
+// Package newsapitest provides a fake newsapi server for integration
+// tests.
+package newsapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+)
+
+const (
+	// _defaultPageSize is the page size used when it is not specified in
+	// the request.
+	_defaultPageSize = 20
+
+	// _maxPageSize is the maximum allowed page size.
+	_maxPageSize = 100
+
+	// _defaultMaxResults is the default number of results that can be
+	// paged through.
+	_defaultMaxResults = 100
+
+	// _timeLayout is the layout of from and to parameters.
+	_timeLayout = "2006-01-02T15:04:05"
+)
+
+// Option is used to set server configuration options.
+type Option func(s *Server)
+
+// WithAPIKey makes the server require the provided api key. By default,
+// any api key is accepted.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithArticles seeds the server with articles. Language, country and
+// category of an article are taken from the source with a matching id.
+func WithArticles(articles ...newsapi.Article) Option {
+	return func(s *Server) {
+		s.articles = append(s.articles, articles...)
+	}
+}
+
+// WithSources seeds the server with sources.
+func WithSources(sources ...newsapi.Source) Option {
+	return func(s *Server) {
+		s.sources = append(s.sources, sources...)
+	}
+}
+
+// WithMaxResults sets the number of results that can be paged through,
+// after which maximumResultsReached error is returned. 100 is default.
+func WithMaxResults(n int) Option {
+	return func(s *Server) {
+		s.maxResults = n
+	}
+}
+
+// WithLatency delays every response by the provided duration.
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// Request contains information about a request received by the server.
+type Request struct {
+	// Endpoint specifies the requested endpoint.
+	Endpoint newsapi.Endpoint
+
+	// Query specifies the query parameters of the request.
+	Query url.Values
+}
+
+// Server is a fake newsapi server that serves everything, top headlines
+// and sources endpoints over an in-memory corpus of articles and sources.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	apiKey     string
+	articles   []newsapi.Article
+	sources    []newsapi.Source
+	maxResults int
+	latency    time.Duration
+	failures   []Failure
+	requests   []Request
+}
+
+// NewServer creates and starts a fresh instance of fake server. The
+// server should be closed once it is no longer needed.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		maxResults: _defaultMaxResults,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+string(newsapi.EndpointEverything), s.handle(newsapi.EndpointEverything, s.everything))
+	mux.HandleFunc("/"+string(newsapi.EndpointTopHeadlines), s.handle(newsapi.EndpointTopHeadlines, s.topHeadlines))
+	mux.HandleFunc("/"+string(newsapi.EndpointSources), s.handle(newsapi.EndpointSources, s.sourceList))
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client creates a newsapi client that sends requests to the server.
+// Additional options are applied after the base url and http client
+// options.
+func (s *Server) Client(opts ...newsapi.ClientOption) *newsapi.Client {
+	apiKey := s.apiKey
+	if apiKey == "" {
+		apiKey = "newsapitest"
+	}
+
+	return newsapi.NewClient(apiKey, append([]newsapi.ClientOption{
+		newsapi.WithBaseURL(s.URL + "/"),
+		newsapi.WithHTTPClient(s.Server.Client()),
+	}, opts...)...)
+}
+
+// AddArticles adds articles to the corpus.
+func (s *Server) AddArticles(articles ...newsapi.Article) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.articles = append(s.articles, articles...)
+}
+
+// AddSources adds sources to the corpus.
+func (s *Server) AddSources(sources ...newsapi.Source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources = append(s.sources, sources...)
+}
+
+// SetLatency sets the duration by which every response is delayed.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// Fail queues failures that are returned instead of regular responses,
+// one per request, in the provided order.
+func (s *Server) Fail(failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failures...)
+}
+
+// Requests returns all requests received by the server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// handle wraps the endpoint handler with request recording, latency,
+// authentication and failure injection.
+func (s *Server) handle(endpoint newsapi.Endpoint, h func(q url.Values) (int, interface{})) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Endpoint: endpoint,
+			Query:    q,
+		})
+
+		latency := s.latency
+
+		var failure Failure
+		if len(s.failures) > 0 {
+			failure = s.failures[0]
+			s.failures = s.failures[1:]
+		}
+
+		apiKey := s.apiKey
+		s.mu.Unlock()
+
+		if latency > 0 {
+			timer := time.NewTimer(latency)
+
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		if failure != nil {
+			failure(w)
+			return
+		}
+
+		key := r.Header.Get("X-Api-Key")
+		if key == "" {
+			key = q.Get("apiKey")
+		}
+
+		switch {
+		case apiKey == "":
+		case key == "":
+			writeError(w, http.StatusUnauthorized, newsapi.APICodeAPIKeyMissing, "Your API key is missing.")
+			return
+		case key != apiKey:
+			writeError(w, http.StatusUnauthorized, newsapi.APICodeAPIKeyInvalid, "Your API key is invalid or incorrect.")
+			return
+		}
+
+		s.mu.Lock()
+		status, body := h(q)
+		s.mu.Unlock()
+
+		writeJSON(w, status, body)
+	}
+}
+
+// everything serves everything endpoint.
+func (s *Server) everything(q url.Values) (int, interface{}) {
+	if q.Get("q") == "" && q.Get("qInTitle") == "" &&
+		q.Get("sources") == "" && q.Get("domains") == "" {
+
+		return errorBody(
+			http.StatusBadRequest,
+			newsapi.APICodeParametersMissing,
+			"Required parameters are missing, the scope of your search is too broad.",
+		)
+	}
+
+	from, err := parseTime(q.Get("from"))
+	if err != nil {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid from parameter.")
+	}
+
+	to, err := parseTime(q.Get("to"))
+	if err != nil {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid to parameter.")
+	}
+
+	sources := splitList(q["sources"])
+	if len(sources) > 20 {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeSourcesTooMany, "You have requested too many sources.")
+	}
+
+	var searchIn []newsapi.SearchIn
+	for _, si := range splitList([]string{q.Get("searchIn")}) {
+		searchIn = append(searchIn, newsapi.SearchIn(si))
+	}
+
+	queryMatcher, err := compileQuery(q.Get("q"), searchIn...)
+	if err != nil {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid q or searchIn parameter.")
+	}
+
+	titleMatcher, err := compileQuery(q.Get("qInTitle"), newsapi.SearchInTitle)
+	if err != nil {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid qInTitle parameter.")
+	}
+
+	domains := splitList(q["domains"])
+	excludeDomains := splitList(q["excludeDomains"])
+	language := newsapi.Language(q.Get("language"))
+
+	var articles []newsapi.Article
+
+	for _, article := range s.articles {
+		source, _ := s.source(article.Source.ID)
+
+		switch {
+		case !matches(queryMatcher, article),
+			!matches(titleMatcher, article),
+			len(sources) > 0 && !contains(sources, article.Source.ID),
+			len(domains) > 0 && !matchDomain(article.URL, domains),
+			len(excludeDomains) > 0 && matchDomain(article.URL, excludeDomains),
+			!from.IsZero() && article.PublishedAt.Before(from),
+			!to.IsZero() && article.PublishedAt.After(to),
+			language != "" && source.Language != language:
+
+			continue
+		}
+
+		articles = append(articles, article)
+	}
+
+	return s.page(q, articles)
+}
+
+// topHeadlines serves top headlines endpoint.
+func (s *Server) topHeadlines(q url.Values) (int, interface{}) {
+	sources := splitList(q["sources"])
+	country := newsapi.Country(q.Get("country"))
+	category := newsapi.Category(q.Get("category"))
+	language := newsapi.Language(q.Get("language"))
+
+	if len(sources) > 0 && (country != "" || category != "") {
+		return errorBody(
+			http.StatusBadRequest,
+			newsapi.APICodeParameterInvalid,
+			"You cannot mix the sources parameter with the country or category parameters.",
+		)
+	}
+
+	if q.Get("q") == "" && len(sources) == 0 &&
+		country == "" && category == "" && language == "" {
+
+		return errorBody(
+			http.StatusBadRequest,
+			newsapi.APICodeParametersMissing,
+			"Required parameters are missing. Please set any of the following parameters and try again: sources, q, language, country, category.",
+		)
+	}
+
+	queryMatcher, err := compileQuery(q.Get("q"), newsapi.SearchInTitle, newsapi.SearchInDescription)
+	if err != nil {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid q parameter.")
+	}
+
+	var articles []newsapi.Article
+
+	for _, article := range s.articles {
+		source, _ := s.source(article.Source.ID)
+
+		switch {
+		case !matches(queryMatcher, article),
+			len(sources) > 0 && !contains(sources, article.Source.ID),
+			country != "" && source.Country != country,
+			category != "" && source.Category != category,
+			language != "" && source.Language != language:
+
+			continue
+		}
+
+		articles = append(articles, article)
+	}
+
+	return s.page(q, articles)
+}
+
+// sourceList serves sources endpoint. Like newsapi, it honors only the
+// first value of every filter.
+func (s *Server) sourceList(q url.Values) (int, interface{}) {
+	category := newsapi.Category(q.Get("category"))
+	language := newsapi.Language(q.Get("language"))
+	country := newsapi.Country(q.Get("country"))
+
+	sources := []newsapi.Source{}
+
+	for _, source := range s.sources {
+		switch {
+		case category != "" && source.Category != category,
+			language != "" && source.Language != language,
+			country != "" && source.Country != country:
+
+			continue
+		}
+
+		sources = append(sources, source)
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"sources": sources,
+	}
+}
+
+// page sorts the articles and returns the requested page of them.
+// Articles are sorted by publication time, newest first, unless other
+// sort key is requested, in which case the corpus order is kept.
+func (s *Server) page(q url.Values, articles []newsapi.Article) (int, interface{}) {
+	pageSize, err := parseUint(q.Get("pageSize"), _defaultPageSize)
+	if err != nil || pageSize == 0 || pageSize > _maxPageSize {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid pageSize parameter.")
+	}
+
+	page, err := parseUint(q.Get("page"), 1)
+	if err != nil || page == 0 {
+		return errorBody(http.StatusBadRequest, newsapi.APICodeParameterInvalid, "Invalid page parameter.")
+	}
+
+	offset := (page - 1) * pageSize
+	if offset >= s.maxResults && offset > 0 {
+		return errorBody(
+			http.StatusUpgradeRequired,
+			newsapi.APICodeMaximumResultsReached,
+			"You have requested too many results.",
+		)
+	}
+
+	if q.Get("sortBy") == string(newsapi.SortByPublishedAt) || q.Get("sortBy") == "" {
+		sort.SliceStable(articles, func(i, j int) bool {
+			return articles[i].PublishedAt.After(articles[j].PublishedAt)
+		})
+	}
+
+	total := len(articles)
+
+	end := offset + pageSize
+	if end > s.maxResults {
+		end = s.maxResults
+	}
+
+	if end > total {
+		end = total
+	}
+
+	pageArticles := []newsapi.Article{}
+	if offset < end {
+		pageArticles = append(pageArticles, articles[offset:end]...)
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"status":       "ok",
+		"totalResults": total,
+		"articles":     pageArticles,
+	}
+}
+
+// source looks up the source by its id.
+func (s *Server) source(id string) (newsapi.Source, bool) {
+	for _, source := range s.sources {
+		if source.ID == id {
+			return source, true
+		}
+	}
+
+	return newsapi.Source{}, false
+}
+
+// compileQuery creates an article matcher for the query parameter. Nil
+// matcher is returned if the query is empty.
+func compileQuery(q string, searchIn ...newsapi.SearchIn) (*newsapi.ArticleMatcher, error) {
+	if q == "" {
+		return nil, nil
+	}
+
+	return newsapi.NewArticleMatcher(q, searchIn...)
+}
+
+// matches reports whether the article matches the matcher. Nil matcher
+// matches every article.
+func matches(m *newsapi.ArticleMatcher, article newsapi.Article) bool {
+	return m == nil || m.Match(article).Matched
+}
+
+// matchDomain reports whether the host of the url belongs to any of the
+// domains.
+func matchDomain(rawURL string, domains []string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList splits repeated and comma separated parameter values.
+func splitList(values []string) []string {
+	var res []string
+
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				res = append(res, v)
+			}
+		}
+	}
+
+	return res
+}
+
+// contains checks if the value is in the list.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTime parses from and to parameters.
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, _timeLayout, "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Parse(_timeLayout, v)
+}
+
+// parseUint parses an unsigned integer parameter, returning the default
+// value if it is empty.
+func parseUint(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// errorBody creates newsapi error payload.
+func errorBody(status int, code newsapi.APICode, message string) (int, interface{}) {
+	return status, map[string]interface{}{
+		"status":  "error",
+		"code":    code,
+		"message": message,
+	}
+}
+
+// writeError writes newsapi error payload.
+func writeError(w http.ResponseWriter, status int, code newsapi.APICode, message string) {
+	status, body := errorBody(status, code, message)
+	writeJSON(w, status, body)
+}
+
+// writeJSON writes the body as JSON.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
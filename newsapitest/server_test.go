@@ -0,0 +1,329 @@
+package newsapitest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jellydator/newsapi-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer(opts ...Option) *Server {
+	tstamp := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	return NewServer(append([]Option{
+		WithSources(
+			newsapi.Source{
+				SourceID: newsapi.SourceID{ID: "bbc", Name: "BBC"},
+				URL:      "https://www.bbc.co.uk",
+				Category: newsapi.CategoryGeneral,
+				Language: newsapi.LanguageEnglish,
+				Country:  newsapi.CountryUnitedKingdom,
+			},
+			newsapi.Source{
+				SourceID: newsapi.SourceID{ID: "spiegel", Name: "Spiegel"},
+				URL:      "https://www.spiegel.de",
+				Category: newsapi.CategoryGeneral,
+				Language: newsapi.LanguageGerman,
+				Country:  newsapi.CountryGermany,
+			},
+			newsapi.Source{
+				SourceID: newsapi.SourceID{ID: "wired", Name: "Wired"},
+				URL:      "https://www.wired.com",
+				Category: newsapi.CategoryTechnology,
+				Language: newsapi.LanguageEnglish,
+				Country:  newsapi.CountryUnitedStates,
+			},
+		),
+		WithArticles(
+			newsapi.Article{
+				Source:      newsapi.SourceID{ID: "bbc", Name: "BBC"},
+				Title:       "Bitcoin price falls",
+				Description: "Crypto markets are down",
+				URL:         "https://www.bbc.co.uk/news/1",
+				PublishedAt: tstamp,
+			},
+			newsapi.Article{
+				Source:      newsapi.SourceID{ID: "spiegel", Name: "Spiegel"},
+				Title:       "Bitcoin Kurs fällt",
+				Description: "Krypto",
+				URL:         "https://www.spiegel.de/1",
+				PublishedAt: tstamp.Add(time.Hour),
+			},
+			newsapi.Article{
+				Source:      newsapi.SourceID{ID: "wired", Name: "Wired"},
+				Title:       "New phone released",
+				Description: "Bitcoin wallet included",
+				URL:         "https://wired.com/1",
+				PublishedAt: tstamp.Add(2 * time.Hour),
+			},
+		),
+	}, opts...)...)
+}
+
+func titles(articles []newsapi.Article) []string {
+	res := []string{}
+	for _, article := range articles {
+		res = append(res, article.Title)
+	}
+
+	return res
+}
+
+func Test_Server_Everything(t *testing.T) {
+	tstamp := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	tests := map[string]struct {
+		Params newsapi.EverythingParams
+		Titles []string
+		Total  uint
+	}{
+		"Query": {
+			Params: newsapi.EverythingParams{Query: "bitcoin"},
+			Titles: []string{"New phone released", "Bitcoin Kurs fällt", "Bitcoin price falls"},
+			Total:  3,
+		},
+		"Advanced query": {
+			Params: newsapi.EverythingParams{Query: `"bitcoin price" OR (bitcoin NOT wallet NOT kurs)`},
+			Titles: []string{"Bitcoin price falls"},
+			Total:  1,
+		},
+		"Query in title": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", SearchIn: newsapi.SearchInTitle},
+			Titles: []string{"Bitcoin Kurs fällt", "Bitcoin price falls"},
+			Total:  2,
+		},
+		"Sources": {
+			Params: newsapi.EverythingParams{Sources: []string{"bbc", "wired"}},
+			Titles: []string{"New phone released", "Bitcoin price falls"},
+			Total:  2,
+		},
+		"Domains": {
+			Params: newsapi.EverythingParams{Domains: []string{"bbc.co.uk", "wired.com"}},
+			Titles: []string{"New phone released", "Bitcoin price falls"},
+			Total:  2,
+		},
+		"Exclude domains": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", ExcludeDomains: []string{"spiegel.de"}},
+			Titles: []string{"New phone released", "Bitcoin price falls"},
+			Total:  2,
+		},
+		"Time range": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", From: tstamp.Add(time.Minute), To: tstamp.Add(time.Hour)},
+			Titles: []string{"Bitcoin Kurs fällt"},
+			Total:  1,
+		},
+		"Language": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", Language: newsapi.LanguageEnglish},
+			Titles: []string{"New phone released", "Bitcoin price falls"},
+			Total:  2,
+		},
+		"Relevancy keeps corpus order": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", SortBy: newsapi.SortByRelevancy},
+			Titles: []string{"Bitcoin price falls", "Bitcoin Kurs fällt", "New phone released"},
+			Total:  3,
+		},
+		"Paging": {
+			Params: newsapi.EverythingParams{Query: "bitcoin", PageSize: 2, Page: 2},
+			Titles: []string{"Bitcoin price falls"},
+			Total:  3,
+		},
+		"No results": {
+			Params: newsapi.EverythingParams{Query: "ethereum"},
+			Titles: []string{},
+		},
+	}
+
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			articles, total, err := client.Everything(context.Background(), test.Params)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Titles, titles(articles))
+			assert.Equal(t, test.Total, total)
+		})
+	}
+}
+
+func Test_Server_TopHeadlines(t *testing.T) {
+	tests := map[string]struct {
+		Params newsapi.TopHeadlinesParams
+		Titles []string
+	}{
+		"Country": {
+			Params: newsapi.TopHeadlinesParams{Country: newsapi.CountryGermany},
+			Titles: []string{"Bitcoin Kurs fällt"},
+		},
+		"Category": {
+			Params: newsapi.TopHeadlinesParams{Category: newsapi.CategoryGeneral},
+			Titles: []string{"Bitcoin Kurs fällt", "Bitcoin price falls"},
+		},
+		"Language and query": {
+			Params: newsapi.TopHeadlinesParams{Language: newsapi.LanguageEnglish, Query: "wallet"},
+			Titles: []string{"New phone released"},
+		},
+		"Sources": {
+			Params: newsapi.TopHeadlinesParams{Sources: []string{"wired"}},
+			Titles: []string{"New phone released"},
+		},
+	}
+
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			articles, _, err := client.TopHeadlines(context.Background(), test.Params)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Titles, titles(articles))
+		})
+	}
+}
+
+func Test_Server_Sources(t *testing.T) {
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	sources, err := srv.Client().Sources(context.Background(), newsapi.SourceParams{
+		Languages: []newsapi.Language{newsapi.LanguageEnglish},
+	})
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "bbc", sources[0].ID)
+	assert.Equal(t, "wired", sources[1].ID)
+
+	// Only the first value of a filter is honored by the server, so the
+	// client requests every value separately.
+	sources, err = srv.Client().Sources(context.Background(), newsapi.SourceParams{
+		Countries: []newsapi.Country{newsapi.CountryGermany, newsapi.CountryUnitedStates},
+	})
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "spiegel", sources[0].ID)
+	assert.Equal(t, "wired", sources[1].ID)
+}
+
+func Test_Server_errors(t *testing.T) {
+	srv := testServer(WithAPIKey("777"), WithMaxResults(2))
+	t.Cleanup(srv.Close)
+
+	_, _, err := srv.Client().Everything(context.Background(), newsapi.EverythingParams{
+		Query:    "bitcoin",
+		PageSize: 2,
+		Page:     2,
+	})
+	assert.ErrorIs(t, err, newsapi.ErrMaximumResultsReached)
+
+	_, _, err = newsapi.NewClient(
+		"123",
+		newsapi.WithBaseURL(srv.URL+"/"),
+	).Everything(context.Background(), newsapi.EverythingParams{Query: "bitcoin"})
+	assert.ErrorIs(t, err, newsapi.ErrAPIKeyInvalid)
+
+	_, _, err = newsapi.NewClient(
+		"",
+		newsapi.WithBaseURL(srv.URL+"/"),
+	).Everything(context.Background(), newsapi.EverythingParams{Query: "bitcoin"})
+	assert.ErrorIs(t, err, newsapi.ErrAPIKeyMissing)
+
+	resp, err := http.Get(srv.URL + "/everything?apiKey=777")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/everything?apiKey=777&q=bitcoin&from=test")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	_, _, err = srv.Client().Everything(context.Background(), newsapi.EverythingParams{
+		Query: "bitcoin AND",
+	})
+	assert.ErrorIs(t, err, newsapi.ErrParameterInvalid)
+
+	resp, err = http.Get(srv.URL + "/top-headlines?apiKey=777&sources=bbc&country=gb")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Server_Fail(t *testing.T) {
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	srv.Fail(RateLimited(time.Minute), ServerError(), MalformedJSON())
+
+	client := srv.Client()
+	params := newsapi.EverythingParams{Query: "bitcoin"}
+
+	_, _, err := client.Everything(context.Background(), params)
+	assert.ErrorIs(t, err, newsapi.ErrRateLimited)
+
+	var apiErr *newsapi.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, time.Minute, apiErr.RetryAfter)
+
+	_, _, err = client.Everything(context.Background(), params)
+	assert.ErrorIs(t, err, newsapi.ErrUnexpectedError)
+
+	_, _, err = client.Everything(context.Background(), params)
+	assert.Error(t, err)
+
+	_, _, err = client.Everything(context.Background(), params)
+	assert.NoError(t, err)
+
+	assert.Len(t, srv.Requests(), 4)
+	assert.Equal(t, newsapi.EndpointEverything, srv.Requests()[0].Endpoint)
+	assert.Equal(t, "bitcoin", srv.Requests()[0].Query.Get("q"))
+}
+
+func Test_Server_SetLatency(t *testing.T) {
+	srv := testServer()
+	t.Cleanup(srv.Close)
+
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := srv.Client().Sources(ctx, newsapi.SourceParams{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_Server_AddArticles(t *testing.T) {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddSources(newsapi.Source{SourceID: newsapi.SourceID{ID: "test"}})
+	srv.AddArticles(newsapi.Article{Source: newsapi.SourceID{ID: "test"}, Title: "test"})
+
+	articles, total, err := srv.Client().Everything(context.Background(), newsapi.EverythingParams{
+		Sources: []string{"test"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), total)
+	assert.Equal(t, []string{"test"}, titles(articles))
+
+	sources, err := srv.Client().Sources(context.Background(), newsapi.SourceParams{})
+	require.NoError(t, err)
+	assert.Len(t, sources, 1)
+}
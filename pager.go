@@ -0,0 +1,121 @@
+package newsapi
+
+import (
+	"context"
+	"errors"
+)
+
+// _defaultPageSize is the page size used by newsapi when it is not
+// specified in the parameters.
+const _defaultPageSize = 20
+
+// ArticlePager iterates over articles spread across multiple pages.
+// Pages are retrieved lazily, only when all articles of the previous page
+// have been consumed.
+type ArticlePager struct {
+	fetch    func(ctx context.Context, page uint) ([]Article, uint, error)
+	page     uint
+	pageSize uint
+	fetched  uint
+	total    uint
+	articles []Article
+	article  Article
+	done     bool
+	err      error
+}
+
+// EverythingPager creates a pager that retrieves articles from the
+// everything endpoint page by page, starting with the page specified in
+// the parameters.
+func (c *Client) EverythingPager(pr EverythingParams) *ArticlePager {
+	return newArticlePager(pr.Page, pr.PageSize, func(ctx context.Context, page uint) ([]Article, uint, error) {
+		pr.Page = page
+		return c.Everything(ctx, pr)
+	})
+}
+
+// TopHeadlinesPager creates a pager that retrieves articles from the top
+// headlines endpoint page by page, starting with the page specified in
+// the parameters.
+func (c *Client) TopHeadlinesPager(pr TopHeadlinesParams) *ArticlePager {
+	return newArticlePager(pr.Page, pr.PageSize, func(ctx context.Context, page uint) ([]Article, uint, error) {
+		pr.Page = page
+		return c.TopHeadlines(ctx, pr)
+	})
+}
+
+// newArticlePager creates a fresh instance of article pager.
+func newArticlePager(page, pageSize uint, fetch func(ctx context.Context, page uint) ([]Article, uint, error)) *ArticlePager {
+	if page == 0 {
+		page = 1
+	}
+
+	if pageSize == 0 {
+		pageSize = _defaultPageSize
+	}
+
+	return &ArticlePager{
+		fetch:    fetch,
+		page:     page,
+		pageSize: pageSize,
+		fetched:  (page - 1) * pageSize,
+	}
+}
+
+// Next advances the pager to the next article, which then can be accessed
+// using Article method. Next returns false when there are no more articles
+// left or when an error occurs, in which case Err method returns it.
+func (p *ArticlePager) Next(ctx context.Context) bool {
+	for len(p.articles) == 0 {
+		if p.done || p.err != nil {
+			return false
+		}
+
+		p.fetchPage(ctx)
+	}
+
+	p.article = p.articles[0]
+	p.articles = p.articles[1:]
+
+	return true
+}
+
+// Article returns the current article.
+func (p *ArticlePager) Article() Article {
+	return p.article
+}
+
+// Err returns the first error that occurred during pagination.
+func (p *ArticlePager) Err() error {
+	return p.err
+}
+
+// Total returns the number of available articles as reported by the
+// most recently retrieved page.
+func (p *ArticlePager) Total() uint {
+	return p.total
+}
+
+// fetchPage retrieves the next page and advances the page cursor.
+func (p *ArticlePager) fetchPage(ctx context.Context) {
+	articles, total, err := p.fetch(ctx, p.page)
+	if err != nil {
+		if errors.Is(err, ErrMaximumResultsReached) {
+			p.done = true
+			return
+		}
+
+		p.err = err
+
+		return
+	}
+
+	p.page++
+	p.total = total
+	p.fetched += uint(len(articles))
+	p.articles = articles
+
+	if uint(len(articles)) < p.pageSize || p.fetched >= total {
+		p.done = true
+	}
+}
@@ -0,0 +1,24 @@
+//go:build go1.23
+
+package newsapi
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the remaining articles. If an error occurs,
+// it is yielded along with a zero value article and the iteration stops.
+func (p *ArticlePager) All(ctx context.Context) iter.Seq2[Article, error] {
+	return func(yield func(Article, error) bool) {
+		for p.Next(ctx) {
+			if !yield(p.Article(), nil) {
+				return
+			}
+		}
+
+		if err := p.Err(); err != nil {
+			yield(Article{}, err)
+		}
+	}
+}
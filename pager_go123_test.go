@@ -0,0 +1,47 @@
+//go:build go1.23
+
+package newsapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ArticlePager_All(t *testing.T) {
+	pager := newArticlePager(0, 1, func(_ context.Context, page uint) ([]Article, uint, error) {
+		if page > 2 {
+			return nil, 0, assert.AnError
+		}
+
+		return []Article{{Title: "1"}}, 100, nil
+	})
+
+	var (
+		titles []string
+		errs   []error
+	)
+
+	for article, err := range pager.All(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		titles = append(titles, article.Title)
+	}
+
+	assert.Equal(t, []string{"1", "1"}, titles)
+	assert.Equal(t, []error{assert.AnError}, errs)
+
+	pager = newArticlePager(0, 1, func(_ context.Context, _ uint) ([]Article, uint, error) {
+		return []Article{{Title: "1"}}, 100, nil
+	})
+
+	for range pager.All(context.Background()) {
+		break
+	}
+
+	assert.True(t, pager.Next(context.Background()))
+}
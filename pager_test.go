@@ -0,0 +1,204 @@
+package newsapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Client_EverythingPager(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	client := &Client{
+		client: &http.Client{
+			Transport: transport,
+		},
+		baseURL: "test/",
+	}
+
+	transport.RegisterResponder(http.MethodGet, "test/everything", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "321", req.URL.Query().Get("q"))
+		assert.Equal(t, "2", req.URL.Query().Get("pageSize"))
+
+		switch req.URL.Query().Get("page") {
+		case "1":
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				`{"status":"ok","totalResults":3,"articles":[{"title":"1"},{"title":"2"}]}`,
+			), nil
+		case "2":
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				`{"status":"ok","totalResults":3,"articles":[{"title":"3"}]}`,
+			), nil
+		}
+
+		return nil, assert.AnError
+	})
+
+	pager := client.EverythingPager(EverythingParams{
+		Query:    "321",
+		PageSize: 2,
+	})
+
+	var titles []string
+	for pager.Next(context.Background()) {
+		titles = append(titles, pager.Article().Title)
+	}
+
+	require.NoError(t, pager.Err())
+	assert.Equal(t, []string{"1", "2", "3"}, titles)
+	assert.Equal(t, uint(3), pager.Total())
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
+
+func Test_Client_TopHeadlinesPager(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	client := &Client{
+		client: &http.Client{
+			Transport: transport,
+		},
+		baseURL: "test/",
+	}
+
+	transport.RegisterResponder(http.MethodGet, "test/top-headlines", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "3", req.URL.Query().Get("page"))
+		return httpmock.NewStringResponse(
+			http.StatusOK,
+			`{"status":"ok","totalResults":41,"articles":[{"title":"1"}]}`,
+		), nil
+	})
+
+	pager := client.TopHeadlinesPager(TopHeadlinesParams{
+		Query: "321",
+		Page:  3,
+	})
+
+	require.True(t, pager.Next(context.Background()))
+	assert.Equal(t, "1", pager.Article().Title)
+	assert.False(t, pager.Next(context.Background()))
+	require.NoError(t, pager.Err())
+	assert.Equal(t, uint(41), pager.Total())
+}
+
+func Test_ArticlePager_Next(t *testing.T) {
+	page := func(from, to int) []Article {
+		var articles []Article
+		for i := from; i < to; i++ {
+			articles = append(articles, Article{Title: fmt.Sprint(i)})
+		}
+
+		return articles
+	}
+
+	tests := map[string]struct {
+		Page     uint
+		PageSize uint
+		Pages    map[uint][]Article
+		Errs     map[uint]error
+		Total    uint
+		Count    int
+		Calls    int
+		Err      error
+	}{
+		"Stops on short page": {
+			PageSize: 2,
+			Pages: map[uint][]Article{
+				1: page(0, 2),
+				2: page(2, 3),
+			},
+			Total: 100,
+			Count: 3,
+			Calls: 2,
+		},
+		"Stops on total reached": {
+			PageSize: 2,
+			Pages: map[uint][]Article{
+				1: page(0, 2),
+				2: page(2, 4),
+			},
+			Total: 4,
+			Count: 4,
+			Calls: 2,
+		},
+		"Stops on total reached with initial page": {
+			Page:     2,
+			PageSize: 2,
+			Pages: map[uint][]Article{
+				2: page(2, 4),
+			},
+			Total: 4,
+			Count: 2,
+			Calls: 1,
+		},
+		"Stops on empty page": {
+			Pages: map[uint][]Article{
+				1: page(0, 20),
+				2: nil,
+			},
+			Total: 100,
+			Count: 20,
+			Calls: 2,
+		},
+		"Stops on maximum results reached": {
+			PageSize: 1,
+			Pages: map[uint][]Article{
+				1: page(0, 1),
+			},
+			Errs: map[uint]error{
+				2: &Error{APICode: "maximumResultsReached"},
+			},
+			Total: 100,
+			Count: 1,
+			Calls: 2,
+		},
+		"Fetch returns an error": {
+			PageSize: 1,
+			Pages: map[uint][]Article{
+				1: page(0, 1),
+			},
+			Errs: map[uint]error{
+				2: assert.AnError,
+			},
+			Total: 100,
+			Count: 1,
+			Calls: 2,
+			Err:   assert.AnError,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+
+			pager := newArticlePager(test.Page, test.PageSize, func(_ context.Context, page uint) ([]Article, uint, error) {
+				calls++
+
+				if err := test.Errs[page]; err != nil {
+					return nil, 0, err
+				}
+
+				return test.Pages[page], test.Total, nil
+			})
+
+			var count int
+			for pager.Next(context.Background()) {
+				count++
+			}
+
+			assert.Equal(t, test.Err, pager.Err())
+			assert.Equal(t, test.Count, count)
+			assert.Equal(t, test.Calls, calls)
+			assert.False(t, pager.Next(context.Background()))
+			assert.Equal(t, test.Calls, calls)
+		})
+	}
+}
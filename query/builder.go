@@ -0,0 +1,122 @@
+package query
+
+// Builder builds a query by combining nodes.
+type Builder struct {
+	node Node
+}
+
+// Build creates a fresh instance of builder that starts with the
+// provided node.
+func Build(n Node) *Builder {
+	return &Builder{
+		node: n,
+	}
+}
+
+// Terms creates a builder that starts with the keywords joined with "OR"
+// operator.
+func Terms(values ...string) *Builder {
+	nodes := make([]Node, 0, len(values))
+	for _, v := range values {
+		nodes = append(nodes, Term{Value: v})
+	}
+
+	return Build(or(nodes))
+}
+
+// And requires the nodes to match along with the current query.
+func (b *Builder) And(nodes ...Node) *Builder {
+	return b.and(nodes...)
+}
+
+// Or allows any of the nodes to match instead of the current query.
+func (b *Builder) Or(nodes ...Node) *Builder {
+	all := append([]Node{b.node}, nodes...)
+	if b.node == nil {
+		all = nodes
+	}
+
+	b.node = or(all)
+
+	return b
+}
+
+// AndNot requires the nodes not to match along with the current query.
+func (b *Builder) AndNot(nodes ...Node) *Builder {
+	negated := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		negated = append(negated, Not{Node: n})
+	}
+
+	return b.and(negated...)
+}
+
+// Must requires the nodes to appear in an article.
+func (b *Builder) Must(nodes ...Node) *Builder {
+	required := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		required = append(required, Must{Node: n})
+	}
+
+	return b.and(required...)
+}
+
+// MustNot requires the nodes not to appear in an article.
+func (b *Builder) MustNot(nodes ...Node) *Builder {
+	excluded := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		excluded = append(excluded, MustNot{Node: n})
+	}
+
+	return b.and(excluded...)
+}
+
+// Node returns the root node of the built query.
+func (b *Builder) Node() Node {
+	return b.node
+}
+
+// String renders the built query.
+func (b *Builder) String() string {
+	if b.node == nil {
+		return ""
+	}
+
+	return b.node.String()
+}
+
+// and joins the nodes with the current query using "AND" operator.
+func (b *Builder) and(nodes ...Node) *Builder {
+	var all []Node
+
+	switch n := b.node.(type) {
+	case nil:
+	case And:
+		all = append(all, n.Nodes...)
+	default:
+		all = append(all, n)
+	}
+
+	all = append(all, nodes...)
+
+	if len(all) == 1 {
+		b.node = all[0]
+		return b
+	}
+
+	b.node = And{Nodes: all}
+
+	return b
+}
+
+// or joins the nodes using "OR" operator, unless there is at most one.
+func or(nodes []Node) Node {
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	}
+
+	return Or{Nodes: nodes}
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Builder(t *testing.T) {
+	assert.Equal(
+		t,
+		"crypto AND (ethereum OR litecoin) NOT bitcoin",
+		Build(Term{Value: "crypto"}).
+			And(Terms("ethereum", "litecoin").Node()).
+			AndNot(Term{Value: "bitcoin"}).
+			String(),
+	)
+
+	assert.Equal(
+		t,
+		`+crypto AND -"price drop" OR news`,
+		Build(nil).
+			Must(Term{Value: "crypto"}).
+			MustNot(Phrase{Value: "price drop"}).
+			Or(Term{Value: "news"}).
+			String(),
+	)
+
+	assert.Equal(
+		t,
+		`"Apple Inc" OR Microsoft OR "AND"`,
+		Terms("Apple Inc", "Microsoft", "AND").String(),
+	)
+
+	assert.Equal(t, "a", Build(nil).And(Term{Value: "a"}).String())
+	assert.Equal(t, "a", Build(nil).Or(Term{Value: "a"}).String())
+	assert.Equal(t, "NOT a", Build(nil).AndNot(Term{Value: "a"}).String())
+	assert.Equal(t, "", Terms().String())
+	assert.Nil(t, Terms().Node())
+}
+
+func Test_Builder_parse(t *testing.T) {
+	b := Build(Term{Value: "crypto"}).
+		And(Terms("ethereum", "litecoin 2").Node()).
+		AndNot(Phrase{Value: "bit coin"}).
+		Or(Term{Value: "AND"})
+
+	n, err := Parse(b.String())
+	assert.NoError(t, err)
+	assert.Equal(t, b.String(), n.String())
+}
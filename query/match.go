@@ -0,0 +1,191 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Result contains the outcome of a query evaluation.
+type Result struct {
+	// Matched specifies whether the query matched.
+	Matched bool
+
+	// Terms specifies keywords and phrases of the query, in the order of
+	// their appearance in the query, that were found in the text and
+	// contributed to the match. It is empty when the query did not match.
+	Terms []string
+}
+
+// Matcher evaluates a query against text locally.
+//
+// Text is split into words consisting of letters and digits and compared
+// case-insensitively. A keyword matches when the words it consists of
+// appear consecutively in the text, so "e-mail" matches "E-Mail" and
+// "e mail". Phrases are matched the same way, but never across fields.
+type Matcher struct {
+	node Node
+}
+
+// Compile parses the query and creates a matcher for it.
+func Compile(q string) (*Matcher, error) {
+	n, err := Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMatcher(n), nil
+}
+
+// NewMatcher creates a fresh instance of matcher for the syntax tree.
+func NewMatcher(n Node) *Matcher {
+	return &Matcher{
+		node: n,
+	}
+}
+
+// Match evaluates the query against the text fields.
+func (m *Matcher) Match(fields ...string) Result {
+	doc := make([][]string, 0, len(fields))
+	for _, field := range fields {
+		doc = append(doc, words(field))
+	}
+
+	ev := &evaluation{
+		doc:  doc,
+		seen: make(map[string]struct{}),
+	}
+
+	if !ev.eval(m.node) {
+		return Result{}
+	}
+
+	ev.collect(m.node, false)
+
+	return Result{
+		Matched: true,
+		Terms:   ev.terms,
+	}
+}
+
+// evaluation holds the state of a single query evaluation.
+type evaluation struct {
+	doc   [][]string
+	terms []string
+	seen  map[string]struct{}
+}
+
+// eval evaluates the node against the document.
+func (ev *evaluation) eval(n Node) bool {
+	switch n := n.(type) {
+	case Term:
+		return ev.contains(n.Value)
+	case Phrase:
+		return ev.contains(n.Value)
+	case Must:
+		return ev.eval(n.Node)
+	case MustNot:
+		return !ev.eval(n.Node)
+	case Not:
+		return !ev.eval(n.Node)
+	case Group:
+		return ev.eval(n.Node)
+	case And:
+		for _, child := range n.Nodes {
+			if !ev.eval(child) {
+				return false
+			}
+		}
+
+		return true
+	case Or:
+		for _, child := range n.Nodes {
+			if ev.eval(child) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// collect gathers keywords and phrases that are found in the document
+// and are not negated.
+func (ev *evaluation) collect(n Node, negated bool) {
+	switch n := n.(type) {
+	case Term:
+		ev.addTerm(n.Value, negated)
+	case Phrase:
+		ev.addTerm(n.Value, negated)
+	case Must:
+		ev.collect(n.Node, negated)
+	case MustNot:
+		ev.collect(n.Node, !negated)
+	case Not:
+		ev.collect(n.Node, !negated)
+	case Group:
+		ev.collect(n.Node, negated)
+	case And:
+		for _, child := range n.Nodes {
+			ev.collect(child, negated)
+		}
+	case Or:
+		for _, child := range n.Nodes {
+			ev.collect(child, negated)
+		}
+	}
+}
+
+// addTerm adds the term to the matched terms if it is found in the
+// document.
+func (ev *evaluation) addTerm(term string, negated bool) {
+	if negated || !ev.contains(term) {
+		return
+	}
+
+	if _, ok := ev.seen[term]; ok {
+		return
+	}
+
+	ev.seen[term] = struct{}{}
+	ev.terms = append(ev.terms, term)
+}
+
+// contains checks if the words of the term appear consecutively in any
+// of the document fields.
+func (ev *evaluation) contains(term string) bool {
+	needle := words(term)
+	if len(needle) == 0 {
+		return false
+	}
+
+	for _, field := range ev.doc {
+		for i := 0; i+len(needle) <= len(field); i++ {
+			if equalWords(field[i:i+len(needle)], needle) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// equalWords checks if both word lists are equal.
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// words splits the text into case-folded words consisting of letters and
+// digits.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compile(t *testing.T) {
+	m, err := Compile("bitcoin")
+	require.NoError(t, err)
+	assert.Equal(t, Term{Value: "bitcoin"}, m.node)
+
+	_, err = Compile("bitcoin AND")
+	assert.Error(t, err)
+}
+
+func Test_Matcher_Match(t *testing.T) {
+	fields := []string{
+		"Bitcoin price falls below $30,000",
+		"Crypto markets are down, e-mail newsletter readers say.",
+	}
+
+	tests := map[string]struct {
+		Query  string
+		Result Result
+	}{
+		"Keyword": {
+			Query: "BITCOIN",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"BITCOIN"},
+			},
+		},
+		"Keyword is not matched as a part of a word": {
+			Query: "coin",
+		},
+		"Keyword with punctuation": {
+			Query: "e-mail",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"e-mail"},
+			},
+		},
+		"Phrase": {
+			Query: `"price falls"`,
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"price falls"},
+			},
+		},
+		"Phrase is not matched across fields": {
+			Query: `"30 000 crypto"`,
+		},
+		"Phrase with words in different order": {
+			Query: `"falls price"`,
+		},
+		"Terms in different fields": {
+			Query: "bitcoin AND markets",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"bitcoin", "markets"},
+			},
+		},
+		"Or": {
+			Query: "ethereum OR litecoin OR crypto OR bitcoin",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"crypto", "bitcoin"},
+			},
+		},
+		"Not": {
+			Query: "bitcoin NOT crypto",
+		},
+		"Not negates terms": {
+			Query: "bitcoin NOT ethereum",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"bitcoin"},
+			},
+		},
+		"Must and must not": {
+			Query: "+bitcoin -ethereum -(litecoin OR dogecoin)",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"bitcoin"},
+			},
+		},
+		"Must not": {
+			Query: "+bitcoin -crypto",
+		},
+		"Double negation": {
+			Query: "NOT (bitcoin NOT crypto)",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"crypto"},
+			},
+		},
+		"Groups": {
+			Query: "crypto AND (ethereum OR (bitcoin AND down))",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"crypto", "bitcoin", "down"},
+			},
+		},
+		"Duplicate terms": {
+			Query: "bitcoin OR (bitcoin AND crypto)",
+			Result: Result{
+				Matched: true,
+				Terms:   []string{"bitcoin", "crypto"},
+			},
+		},
+		"Empty phrase": {
+			Query: `bitcoin ""`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := Compile(test.Query)
+			require.NoError(t, err)
+			assert.Equal(t, test.Result, m.Match(fields...))
+		})
+	}
+}
+
+func Test_words(t *testing.T) {
+	assert.Equal(t, []string{"über", "e", "mail", "30", "000"}, words("Über, E-Mail $30,000!"))
+	assert.Empty(t, words(" -- "))
+}
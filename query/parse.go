@@ -0,0 +1,357 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError is returned whenever a query cannot be parsed.
+type SyntaxError struct {
+	// Offset specifies the byte offset in the query at which the error
+	// was found.
+	Offset int
+
+	// Message specifies the description of the error.
+	Message string
+}
+
+// Error implements error interface and returns formatted error message.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query syntax error at position %d: %s", e.Offset, e.Message)
+}
+
+// Parse parses the query into its syntax tree.
+func Parse(q string) (Node, error) {
+	tokens, err := lex(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens: tokens,
+	}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{
+			Offset:  0,
+			Message: "query is empty",
+		}
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+
+	return n, nil
+}
+
+// tokenKind determines the type of a token.
+type tokenKind int
+
+// All available token kinds.
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenPlus
+	tokenMinus
+	tokenLParen
+	tokenRParen
+)
+
+// token is a single lexical token of a query.
+type token struct {
+	kind   tokenKind
+	value  string
+	offset int
+}
+
+// lex splits the query into tokens.
+func lex(q string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(q); {
+		r, size := utf8.DecodeRuneInString(q[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", offset: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", offset: i})
+			i++
+		case r == '"':
+			end := strings.IndexByte(q[i+1:], '"')
+			if end < 0 {
+				return nil, &SyntaxError{
+					Offset:  i,
+					Message: "unterminated phrase",
+				}
+			}
+
+			tokens = append(tokens, token{kind: tokenPhrase, value: q[i+1 : i+1+end], offset: i})
+			i += end + 2
+		case r == '+' || r == '-':
+			kind := tokenPlus
+			if r == '-' {
+				kind = tokenMinus
+			}
+
+			tokens = append(tokens, token{kind: kind, value: string(r), offset: i})
+			i++
+		default:
+			end := i
+			for end < len(q) {
+				r, size := utf8.DecodeRuneInString(q[end:])
+				if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+					break
+				}
+
+				end += size
+			}
+
+			word := q[i:end]
+
+			kind := tokenWord
+			switch word {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+
+			tokens = append(tokens, token{kind: kind, value: word, offset: i})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, offset: len(q)}), nil
+}
+
+// parser builds a syntax tree from tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+// parseOr parses nodes joined with "OR" operator.
+func (p *parser) parseOr() (Node, error) {
+	n, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{n}
+
+	for p.peek().kind == tokenOr {
+		op := p.next()
+
+		if err = p.expectOperand(op); err != nil {
+			return nil, err
+		}
+
+		if n, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return or(nodes), nil
+}
+
+// parseAnd parses nodes joined with "AND" or "NOT" operators or
+// separated only by whitespace.
+func (p *parser) parseAnd() (Node, error) {
+	n, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{n}
+
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			op := p.next()
+
+			if err = p.expectOperand(op); err != nil {
+				return nil, err
+			}
+
+			if n, err = p.parseUnary(); err != nil {
+				return nil, err
+			}
+		case tokenNot:
+			op := p.next()
+
+			if err = p.expectOperand(op); err != nil {
+				return nil, err
+			}
+
+			if n, err = p.parseUnary(); err != nil {
+				return nil, err
+			}
+
+			n = Not{Node: n}
+		case tokenWord, tokenPhrase, tokenPlus, tokenMinus, tokenLParen:
+			if n, err = p.parseUnary(); err != nil {
+				return nil, err
+			}
+		default:
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+
+			return And{Nodes: nodes}, nil
+		}
+
+		nodes = append(nodes, n)
+	}
+}
+
+// parseUnary parses a node optionally prefixed with "NOT" operator or
+// with a plus or minus sign.
+func (p *parser) parseUnary() (Node, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		op := p.next()
+
+		if err := p.expectOperand(op); err != nil {
+			return nil, err
+		}
+
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{Node: n}, nil
+	case tokenPlus, tokenMinus:
+		op := p.next()
+
+		if next := p.peek(); next.offset != op.offset+1 ||
+			(next.kind != tokenWord && next.kind != tokenPhrase && next.kind != tokenLParen) {
+
+			return nil, &SyntaxError{
+				Offset:  op.offset,
+				Message: fmt.Sprintf("%q must be directly followed by a keyword, a phrase or a group", op.value),
+			}
+		}
+
+		n, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		if op.kind == tokenPlus {
+			return Must{Node: n}, nil
+		}
+
+		return MustNot{Node: n}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a keyword, a phrase or a group.
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenWord:
+		return Term{Value: tok.value}, nil
+	case tokenPhrase:
+		return Phrase{Value: tok.value}, nil
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, &SyntaxError{
+				Offset:  tok.offset,
+				Message: "empty parentheses",
+			}
+		}
+
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if next := p.peek(); next.kind != tokenRParen {
+			if next.kind == tokenEOF {
+				return nil, &SyntaxError{
+					Offset:  tok.offset,
+					Message: "unbalanced parentheses: missing closing parenthesis",
+				}
+			}
+
+			return nil, p.unexpected(next)
+		}
+
+		p.next()
+
+		return Group{Node: n}, nil
+	}
+
+	return nil, p.unexpected(tok)
+}
+
+// expectOperand checks that the operator is followed by an operand.
+func (p *parser) expectOperand(op token) error {
+	switch p.peek().kind {
+	case tokenEOF, tokenRParen, tokenAnd, tokenOr:
+		return &SyntaxError{
+			Offset:  op.offset,
+			Message: fmt.Sprintf("operator %s is missing its right operand", op.value),
+		}
+	}
+
+	return nil
+}
+
+// unexpected creates an error describing an unexpected token.
+func (p *parser) unexpected(tok token) error {
+	msg := fmt.Sprintf("unexpected %q", tok.value)
+
+	switch tok.kind {
+	case tokenEOF:
+		msg = "unexpected end of query"
+	case tokenRParen:
+		msg = "unbalanced parentheses: unexpected closing parenthesis"
+	case tokenAnd, tokenOr:
+		msg = fmt.Sprintf("operator %s is missing its left operand", tok.value)
+	}
+
+	return &SyntaxError{
+		Offset:  tok.offset,
+		Message: msg,
+	}
+}
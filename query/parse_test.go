@@ -0,0 +1,213 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SyntaxError_Error(t *testing.T) {
+	err := &SyntaxError{
+		Offset:  5,
+		Message: "test",
+	}
+
+	assert.EqualError(t, err, "query syntax error at position 5: test")
+}
+
+func Test_Parse(t *testing.T) {
+	tests := map[string]struct {
+		Query string
+		Node  Node
+		Err   error
+	}{
+		"Single keyword": {
+			Query: "bitcoin",
+			Node:  Term{Value: "bitcoin"},
+		},
+		"Phrase": {
+			Query: `"my short phrase"`,
+			Node:  Phrase{Value: "my short phrase"},
+		},
+		"Implicit and": {
+			Query: "crypto  bitcoin\tüber",
+			Node: And{Nodes: []Node{
+				Term{Value: "crypto"},
+				Term{Value: "bitcoin"},
+				Term{Value: "über"},
+			}},
+		},
+		"Must and must not": {
+			Query: `+bitcoin -"price drop" +(a OR b) e-mail`,
+			Node: And{Nodes: []Node{
+				Must{Node: Term{Value: "bitcoin"}},
+				MustNot{Node: Phrase{Value: "price drop"}},
+				Must{Node: Group{Node: Or{Nodes: []Node{
+					Term{Value: "a"},
+					Term{Value: "b"},
+				}}}},
+				Term{Value: "e-mail"},
+			}},
+		},
+		"Operators": {
+			Query: "crypto AND (ethereum OR litecoin) NOT bitcoin",
+			Node: And{Nodes: []Node{
+				Term{Value: "crypto"},
+				Group{Node: Or{Nodes: []Node{
+					Term{Value: "ethereum"},
+					Term{Value: "litecoin"},
+				}}},
+				Not{Node: Term{Value: "bitcoin"}},
+			}},
+		},
+		"Precedence": {
+			Query: "a OR b AND c OR NOT d",
+			Node: Or{Nodes: []Node{
+				Term{Value: "a"},
+				And{Nodes: []Node{
+					Term{Value: "b"},
+					Term{Value: "c"},
+				}},
+				Not{Node: Term{Value: "d"}},
+			}},
+		},
+		"Nested groups": {
+			Query: "((a))",
+			Node:  Group{Node: Group{Node: Term{Value: "a"}}},
+		},
+		"Lowercase operators are plain keywords": {
+			Query: "a and b",
+			Node: And{Nodes: []Node{
+				Term{Value: "a"},
+				Term{Value: "and"},
+				Term{Value: "b"},
+			}},
+		},
+		"Empty query": {
+			Query: "  ",
+			Err: &SyntaxError{
+				Offset:  0,
+				Message: "query is empty",
+			},
+		},
+		"Unterminated phrase": {
+			Query: `a "bitcoin`,
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: "unterminated phrase",
+			},
+		},
+		"Missing closing parenthesis": {
+			Query: "a AND (b OR (c)",
+			Err: &SyntaxError{
+				Offset:  6,
+				Message: "unbalanced parentheses: missing closing parenthesis",
+			},
+		},
+		"Unexpected closing parenthesis": {
+			Query: "a AND b)",
+			Err: &SyntaxError{
+				Offset:  7,
+				Message: "unbalanced parentheses: unexpected closing parenthesis",
+			},
+		},
+		"Leading closing parenthesis": {
+			Query: ")",
+			Err: &SyntaxError{
+				Offset:  0,
+				Message: "unbalanced parentheses: unexpected closing parenthesis",
+			},
+		},
+		"Empty parentheses": {
+			Query: "a ()",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: "empty parentheses",
+			},
+		},
+		"Dangling operator": {
+			Query: "a AND",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: "operator AND is missing its right operand",
+			},
+		},
+		"Dangling operator in group": {
+			Query: "(a OR) b",
+			Err: &SyntaxError{
+				Offset:  3,
+				Message: "operator OR is missing its right operand",
+			},
+		},
+		"Consecutive operators": {
+			Query: "a AND OR b",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: "operator AND is missing its right operand",
+			},
+		},
+		"Dangling NOT": {
+			Query: "a NOT",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: "operator NOT is missing its right operand",
+			},
+		},
+		"Dangling unary NOT": {
+			Query: "NOT",
+			Err: &SyntaxError{
+				Offset:  0,
+				Message: "operator NOT is missing its right operand",
+			},
+		},
+		"Leading operator": {
+			Query: "OR a",
+			Err: &SyntaxError{
+				Offset:  0,
+				Message: "operator OR is missing its left operand",
+			},
+		},
+		"Detached plus sign": {
+			Query: "a + b",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: `"+" must be directly followed by a keyword, a phrase or a group`,
+			},
+		},
+		"Minus sign followed by an operator": {
+			Query: "a -AND",
+			Err: &SyntaxError{
+				Offset:  2,
+				Message: `"-" must be directly followed by a keyword, a phrase or a group`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := Parse(test.Query)
+			assert.Equal(t, test.Err, err)
+			assert.Equal(t, test.Node, n)
+		})
+	}
+}
+
+func Test_Parse_roundTrip(t *testing.T) {
+	for _, q := range []string{
+		"bitcoin",
+		`"my short phrase"`,
+		"crypto AND (ethereum OR litecoin) NOT bitcoin",
+		`+bitcoin AND -"price drop" AND +(a OR b)`,
+		"a OR b AND c OR NOT d",
+		"NOT (a AND b)",
+	} {
+		n, err := Parse(q)
+		if assert.NoError(t, err, q) {
+			assert.Equal(t, q, n.String())
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package query implements the advanced search syntax of newsapi
+// everything endpoint query parameter.
+//
+// The syntax consists of keywords, exact phrases surrounded with quotes,
+// keywords or phrases prefixed with a plus (must appear) or a minus (must
+// not appear) sign, "AND", "OR" and "NOT" operators and parentheses used
+// to create subgroups, e.g.:
+//
+//	crypto AND (ethereum OR litecoin) NOT bitcoin
+//
+// Keywords separated only by whitespace are treated as if they were
+// joined with "AND". "AND" and "NOT" operators bind tighter than "OR".
+package query
+
+import (
+	"strings"
+)
+
+// Node is a node of the query syntax tree.
+type Node interface {
+	// String renders the node using the query syntax.
+	String() string
+
+	// node prevents the interface from being implemented outside of the
+	// package.
+	node()
+}
+
+// Term is a single keyword.
+type Term struct {
+	// Value specifies the keyword.
+	Value string
+}
+
+// String renders the keyword. Keywords that cannot be expressed as a
+// single word, e.g. those containing whitespace, parentheses or matching
+// an operator, are rendered as phrases.
+func (t Term) String() string {
+	if needsQuoting(t.Value) {
+		return Phrase(t).String()
+	}
+
+	return t.Value
+}
+
+func (Term) node() {}
+
+// Phrase is a phrase that must be matched exactly as is.
+type Phrase struct {
+	// Value specifies the phrase.
+	Value string
+}
+
+// String renders the phrase surrounded with quotes. Quotes cannot be
+// escaped, so they are removed from the phrase.
+func (p Phrase) String() string {
+	return `"` + strings.ReplaceAll(p.Value, `"`, "") + `"`
+}
+
+func (Phrase) node() {}
+
+// Must is a node that must appear in an article.
+type Must struct {
+	// Node specifies the required node.
+	Node Node
+}
+
+// String renders the node prefixed with a plus sign.
+func (m Must) String() string {
+	return "+" + wrapOperand(m.Node)
+}
+
+func (Must) node() {}
+
+// MustNot is a node that must not appear in an article.
+type MustNot struct {
+	// Node specifies the excluded node.
+	Node Node
+}
+
+// String renders the node prefixed with a minus sign.
+func (m MustNot) String() string {
+	return "-" + wrapOperand(m.Node)
+}
+
+func (MustNot) node() {}
+
+// And matches articles that match all of its nodes.
+type And struct {
+	// Nodes specifies the nodes that all must match.
+	Nodes []Node
+}
+
+// String renders the nodes joined with "AND" operator. Negated nodes are
+// joined with "NOT" operator instead.
+func (a And) String() string {
+	var sb strings.Builder
+
+	for i, n := range a.Nodes {
+		if not, ok := n.(Not); ok {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+
+			sb.WriteString(not.String())
+
+			continue
+		}
+
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+
+		if _, ok := n.(Or); ok {
+			sb.WriteString("(" + n.String() + ")")
+			continue
+		}
+
+		sb.WriteString(n.String())
+	}
+
+	return sb.String()
+}
+
+func (And) node() {}
+
+// Or matches articles that match any of its nodes.
+type Or struct {
+	// Nodes specifies the nodes of which at least one must match.
+	Nodes []Node
+}
+
+// String renders the nodes joined with "OR" operator.
+func (o Or) String() string {
+	parts := make([]string, 0, len(o.Nodes))
+	for _, n := range o.Nodes {
+		parts = append(parts, n.String())
+	}
+
+	return strings.Join(parts, " OR ")
+}
+
+func (Or) node() {}
+
+// Not matches articles that do not match its node.
+type Not struct {
+	// Node specifies the negated node.
+	Node Node
+}
+
+// String renders the node prefixed with "NOT" operator.
+func (n Not) String() string {
+	return "NOT " + wrapOperand(n.Node)
+}
+
+func (Not) node() {}
+
+// Group is a node surrounded with parentheses.
+type Group struct {
+	// Node specifies the grouped node.
+	Node Node
+}
+
+// String renders the node surrounded with parentheses.
+func (g Group) String() string {
+	return "(" + g.Node.String() + ")"
+}
+
+func (Group) node() {}
+
+// wrapOperand renders the operand of a unary operator, surrounding it
+// with parentheses if it consists of multiple nodes.
+func wrapOperand(n Node) string {
+	switch n.(type) {
+	case And, Or:
+		return "(" + n.String() + ")"
+	}
+
+	return n.String()
+}
+
+// needsQuoting checks if the keyword must be rendered as a phrase.
+func needsQuoting(v string) bool {
+	if v == "" || isOperator(v) {
+		return true
+	}
+
+	if v[0] == '+' || v[0] == '-' {
+		return true
+	}
+
+	return strings.ContainsAny(v, "()\" \t\r\n")
+}
+
+// isOperator checks if the word is an operator.
+func isOperator(v string) bool {
+	switch v {
+	case "AND", "OR", "NOT":
+		return true
+	}
+
+	return false
+}
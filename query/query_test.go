@@ -0,0 +1,77 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Term_String(t *testing.T) {
+	assert.Equal(t, "bitcoin", Term{Value: "bitcoin"}.String())
+	assert.Equal(t, "e-mail", Term{Value: "e-mail"}.String())
+	assert.Equal(t, `"two words"`, Term{Value: "two words"}.String())
+	assert.Equal(t, `"AND"`, Term{Value: "AND"}.String())
+	assert.Equal(t, `"+1"`, Term{Value: "+1"}.String())
+	assert.Equal(t, `"-1"`, Term{Value: "-1"}.String())
+	assert.Equal(t, `"(a)"`, Term{Value: "(a)"}.String())
+	assert.Equal(t, `"ab"`, Term{Value: `a"b`}.String())
+	assert.Equal(t, `""`, Term{}.String())
+}
+
+func Test_Phrase_String(t *testing.T) {
+	assert.Equal(t, `"short phrase"`, Phrase{Value: "short phrase"}.String())
+	assert.Equal(t, `"short phrase"`, Phrase{Value: `short "phrase"`}.String())
+}
+
+func Test_Must_String(t *testing.T) {
+	assert.Equal(t, "+a", Must{Node: Term{Value: "a"}}.String())
+	assert.Equal(t, "+(a OR b)", Must{Node: Or{Nodes: []Node{
+		Term{Value: "a"},
+		Term{Value: "b"},
+	}}}.String())
+}
+
+func Test_MustNot_String(t *testing.T) {
+	assert.Equal(t, `-"a b"`, MustNot{Node: Phrase{Value: "a b"}}.String())
+	assert.Equal(t, "-(a AND b)", MustNot{Node: And{Nodes: []Node{
+		Term{Value: "a"},
+		Term{Value: "b"},
+	}}}.String())
+}
+
+func Test_And_String(t *testing.T) {
+	assert.Equal(t, "a AND (b OR c) NOT d", And{Nodes: []Node{
+		Term{Value: "a"},
+		Or{Nodes: []Node{
+			Term{Value: "b"},
+			Term{Value: "c"},
+		}},
+		Not{Node: Term{Value: "d"}},
+	}}.String())
+	assert.Equal(t, "NOT a AND b", And{Nodes: []Node{
+		Not{Node: Term{Value: "a"}},
+		Term{Value: "b"},
+	}}.String())
+}
+
+func Test_Or_String(t *testing.T) {
+	assert.Equal(t, "a OR b AND c", Or{Nodes: []Node{
+		Term{Value: "a"},
+		And{Nodes: []Node{
+			Term{Value: "b"},
+			Term{Value: "c"},
+		}},
+	}}.String())
+}
+
+func Test_Not_String(t *testing.T) {
+	assert.Equal(t, "NOT a", Not{Node: Term{Value: "a"}}.String())
+	assert.Equal(t, "NOT (a OR b)", Not{Node: Or{Nodes: []Node{
+		Term{Value: "a"},
+		Term{Value: "b"},
+	}}}.String())
+}
+
+func Test_Group_String(t *testing.T) {
+	assert.Equal(t, "(a)", Group{Node: Term{Value: "a"}}.String())
+}
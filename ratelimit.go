@@ -0,0 +1,90 @@
+package newsapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the number of requests sent within the provided
+// interval. Up to n requests can be sent in a burst, afterwards requests
+// wait until the limit allows them to be sent.
+func WithRateLimit(n uint, interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(n, interval)
+	}
+}
+
+// rateLimiter is a token bucket that limits the rate of requests.
+type rateLimiter struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	capacity float64
+	tokens   float64
+	interval time.Duration
+	last     time.Time
+}
+
+// newRateLimiter creates a fresh instance of rate limiter with a full
+// bucket of n tokens, that is refilled every interval.
+func newRateLimiter(n uint, interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		now:      time.Now,
+		capacity: float64(n),
+		tokens:   float64(n),
+		interval: interval,
+	}
+}
+
+// wait blocks until a token is available or the context is done.
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := rl.take()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take takes a token from the bucket. If the bucket is empty, the delay
+// until the next token becomes available is returned.
+func (rl *rateLimiter) take() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.capacity <= 0 || rl.interval <= 0 {
+		return 0
+	}
+
+	now := rl.now()
+
+	if !rl.last.IsZero() {
+		rl.tokens += float64(now.Sub(rl.last)) / float64(rl.interval) * rl.capacity
+		if rl.tokens > rl.capacity {
+			rl.tokens = rl.capacity
+		}
+	}
+
+	rl.last = now
+
+	if rl.tokens >= 1 {
+		rl.tokens--
+		return 0
+	}
+
+	delay := time.Duration((1 - rl.tokens) / rl.capacity * float64(rl.interval))
+	if delay <= 0 {
+		delay = 1
+	}
+
+	return delay
+}
@@ -0,0 +1,60 @@
+package newsapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithRateLimit(t *testing.T) {
+	c := &Client{}
+	WithRateLimit(5, time.Second)(c)
+
+	require.NotNil(t, c.limiter)
+	assert.Equal(t, float64(5), c.limiter.capacity)
+	assert.Equal(t, time.Second, c.limiter.interval)
+}
+
+func Test_rateLimiter_take(t *testing.T) {
+	now := time.Date(2022, 02, 22, 22, 22, 22, 0, time.UTC)
+
+	rl := newRateLimiter(2, time.Second)
+	rl.now = func() time.Time {
+		return now
+	}
+
+	assert.Zero(t, rl.take())
+	assert.Zero(t, rl.take())
+	assert.Equal(t, 500*time.Millisecond, rl.take())
+
+	now = now.Add(250 * time.Millisecond)
+	assert.Equal(t, 250*time.Millisecond, rl.take())
+
+	now = now.Add(250 * time.Millisecond)
+	assert.Zero(t, rl.take())
+	assert.Equal(t, 500*time.Millisecond, rl.take())
+
+	now = now.Add(time.Hour)
+	assert.Zero(t, rl.take())
+	assert.Zero(t, rl.take())
+	assert.Equal(t, 500*time.Millisecond, rl.take())
+
+	assert.Zero(t, newRateLimiter(0, time.Second).take())
+}
+
+func Test_rateLimiter_wait(t *testing.T) {
+	rl := newRateLimiter(1, time.Hour)
+	require.NoError(t, rl.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, rl.wait(ctx))
+
+	rl = newRateLimiter(1, 10*time.Millisecond)
+	require.NoError(t, rl.wait(context.Background()))
+	require.NoError(t, rl.wait(context.Background()))
+}
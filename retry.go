@@ -0,0 +1,286 @@
+package newsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// _defaultRetryMinBackoff is the delay before the first retry used
+	// when retry policy does not specify it.
+	_defaultRetryMinBackoff = 500 * time.Millisecond
+
+	// _defaultRetryMaxBackoff is the maximum delay between retries used
+	// when retry policy does not specify it.
+	_defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryClassifier reports whether a request attempt should be retried.
+// The status code is zero and the error is non-nil when the request
+// failed before a response was received. The api error is non-nil when
+// newsapi responded with an error payload.
+type RetryClassifier func(statusCode int, apiErr *Error, err error) bool
+
+// RetryPolicy specifies how failed requests should be retried.
+type RetryPolicy struct {
+	// MaxAttempts specifies the maximum number of attempts, including the
+	// initial one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff specifies the delay before the first retry. Every
+	// subsequent delay is doubled. 500ms is default.
+	MinBackoff time.Duration
+
+	// MaxBackoff specifies the maximum delay between two attempts.
+	// 30s is default.
+	MaxBackoff time.Duration
+
+	// Jitter specifies the fraction, between 0 and 1, of every delay
+	// that is randomized.
+	Jitter float64
+
+	// Classifier decides whether an attempt should be retried. If left
+	// empty, DefaultRetryClassifier is used.
+	Classifier RetryClassifier
+}
+
+// WithRetryPolicy sets the policy by which failed requests are retried.
+func WithRetryPolicy(rp RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &rp
+	}
+}
+
+// DefaultRetryClassifier retries transport errors, rate limited responses
+// and server errors. Errors caused by invalid requests or credentials are
+// never retried.
+func DefaultRetryClassifier(statusCode int, apiErr *Error, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrBudgetExhausted)
+	}
+
+	if apiErr != nil {
+		switch apiErr.APICode {
+		case APICodeUnexpectedError, APICodeRateLimited:
+			return true
+		case APICodeAPIKeyDisabled,
+			APICodeAPIKeyExhausted,
+			APICodeAPIKeyInvalid,
+			APICodeAPIKeyMissing,
+			APICodeParameterInvalid,
+			APICodeParametersMissing,
+			APICodeSourcesTooMany,
+			APICodeSourceDoesNotExist,
+			APICodeMaximumResultsReached:
+
+			return false
+		}
+	}
+
+	return statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+// do sends the request using the provided function and retries it
+// according to the policy. The last response or error is returned once
+// the attempts are exhausted, the attempt is classified as final or the
+// request context would expire before the next attempt.
+func (rp *RetryPolicy) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	classify := rp.classifier()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send(req)
+
+		var (
+			statusCode int
+			apiErr     *Error
+		)
+
+		if err == nil {
+			statusCode = resp.StatusCode
+			if apiErr, err = peekError(resp); err != nil {
+				resp = nil
+			}
+		}
+
+		if attempt >= rp.MaxAttempts || !classify(statusCode, apiErr, err) {
+			return resp, err
+		}
+
+		ctx := req.Context()
+
+		delay, ok := rp.delay(ctx, attempt, apiErr)
+		if !ok {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err = sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// call invokes the function and retries it according to the policy. The
+// function's error is classified by the api error it wraps, if any.
+// Validation errors are never retried.
+func (rp *RetryPolicy) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	classify := rp.classifier()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			return err
+		}
+
+		var (
+			statusCode int
+			apiErr     *Error
+			callErr    = err
+		)
+
+		if errors.As(err, &apiErr) {
+			statusCode = apiErr.HTTPCode
+			callErr = nil
+		}
+
+		if attempt >= rp.MaxAttempts || !classify(statusCode, apiErr, callErr) {
+			return err
+		}
+
+		delay, ok := rp.delay(ctx, attempt, apiErr)
+		if !ok {
+			return err
+		}
+
+		if serr := sleep(ctx, delay); serr != nil {
+			return serr
+		}
+	}
+}
+
+// classifier returns the classifier of the policy.
+func (rp *RetryPolicy) classifier() RetryClassifier {
+	if rp.Classifier == nil {
+		return DefaultRetryClassifier
+	}
+
+	return rp.Classifier
+}
+
+// delay calculates the delay before the next attempt, taking the delay
+// advised by newsapi into account. False is returned when the next
+// attempt should not be made, because the advised delay exceeds the
+// maximum backoff or the context would expire before the attempt.
+func (rp *RetryPolicy) delay(ctx context.Context, attempt int, apiErr *Error) (time.Duration, bool) {
+	delay := rp.backoff(attempt)
+
+	if apiErr != nil && apiErr.RetryAfter > delay {
+		if apiErr.RetryAfter > rp.maxBackoff() {
+			return 0, false
+		}
+
+		delay = apiErr.RetryAfter
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// sleep waits for the provided duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff calculates the delay before the next attempt.
+func (rp *RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := rp.MinBackoff, rp.maxBackoff()
+	if minBackoff <= 0 {
+		minBackoff = _defaultRetryMinBackoff
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	if rp.Jitter > 0 {
+		jitter := rp.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// maxBackoff returns the maximum delay between two attempts.
+func (rp *RetryPolicy) maxBackoff() time.Duration {
+	if rp.MaxBackoff <= 0 {
+		return _defaultRetryMaxBackoff
+	}
+
+	return rp.MaxBackoff
+}
+
+// peekError decodes newsapi error payload of an unsuccessful response.
+// The response body is buffered, so it can still be read by the caller.
+func peekError(resp *http.Response) (*Error, error) {
+	if resp.StatusCode == http.StatusOK {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data := struct {
+		Status  string  `json:"status"`
+		Code    APICode `json:"code"`
+		Message string  `json:"message"`
+	}{}
+
+	if err = json.Unmarshal(body, &data); err != nil || data.Status != "error" {
+		return nil, nil
+	}
+
+	return newError(resp, data.Code, data.Message), nil
+}
@@ -0,0 +1,437 @@
+package newsapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithRetryPolicy(t *testing.T) {
+	c := &Client{}
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 3})(c)
+
+	require.NotNil(t, c.retry)
+	assert.Equal(t, 3, c.retry.MaxAttempts)
+}
+
+func Test_DefaultRetryClassifier(t *testing.T) {
+	tests := map[string]struct {
+		StatusCode int
+		APIErr     *Error
+		Err        error
+		Retry      bool
+	}{
+		"Transport error": {
+			Err:   assert.AnError,
+			Retry: true,
+		},
+		"Context canceled": {
+			Err: context.Canceled,
+		},
+		"Context deadline exceeded": {
+			Err: context.DeadlineExceeded,
+		},
+		"Budget exhausted": {
+			Err: ErrBudgetExhausted,
+		},
+		"Rate limited": {
+			StatusCode: http.StatusTooManyRequests,
+			APIErr:     &Error{APICode: "rateLimited"},
+			Retry:      true,
+		},
+		"Unexpected error": {
+			StatusCode: http.StatusInternalServerError,
+			APIErr:     &Error{APICode: "unexpectedError"},
+			Retry:      true,
+		},
+		"Invalid API key": {
+			StatusCode: http.StatusUnauthorized,
+			APIErr:     &Error{APICode: "apiKeyInvalid"},
+		},
+		"Invalid API key with server error status": {
+			StatusCode: http.StatusInternalServerError,
+			APIErr:     &Error{APICode: "apiKeyInvalid"},
+		},
+		"Unknown API code with server error status": {
+			StatusCode: http.StatusBadGateway,
+			APIErr:     &Error{APICode: "123"},
+			Retry:      true,
+		},
+		"Too many requests": {
+			StatusCode: http.StatusTooManyRequests,
+			Retry:      true,
+		},
+		"Server error": {
+			StatusCode: http.StatusServiceUnavailable,
+			Retry:      true,
+		},
+		"Bad request": {
+			StatusCode: http.StatusBadRequest,
+		},
+		"Success": {
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.Retry, DefaultRetryClassifier(test.StatusCode, test.APIErr, test.Err))
+		})
+	}
+}
+
+func Test_RetryPolicy_do(t *testing.T) {
+	unexpected := httpmock.NewStringResponder(
+		http.StatusInternalServerError,
+		`{"status":"error","code":"unexpectedError","message":"bad thing"}`,
+	)
+	invalidKey := httpmock.NewStringResponder(
+		http.StatusUnauthorized,
+		`{"status":"error","code":"apiKeyInvalid","message":"bad key"}`,
+	)
+	success := httpmock.NewStringResponder(http.StatusOK, `{"status":"ok"}`)
+
+	tests := map[string]struct {
+		Policy     RetryPolicy
+		Timeout    time.Duration
+		Resps      []httpmock.Responder
+		Calls      int
+		StatusCode int
+		Body       string
+		Err        error
+	}{
+		"Succeeds after retries": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				Jitter:      0.5,
+			},
+			Resps: []httpmock.Responder{
+				httpmock.NewErrorResponder(assert.AnError),
+				unexpected,
+				success,
+			},
+			Calls:      3,
+			StatusCode: http.StatusOK,
+			Body:       `{"status":"ok"}`,
+		},
+		"Attempts exhausted": {
+			Policy: RetryPolicy{
+				MaxAttempts: 2,
+				MinBackoff:  time.Millisecond,
+			},
+			Resps: []httpmock.Responder{
+				unexpected,
+				unexpected,
+				success,
+			},
+			Calls:      2,
+			StatusCode: http.StatusInternalServerError,
+			Body:       `{"status":"error","code":"unexpectedError","message":"bad thing"}`,
+		},
+		"Non retryable error": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+			},
+			Resps: []httpmock.Responder{
+				invalidKey,
+				success,
+			},
+			Calls:      1,
+			StatusCode: http.StatusUnauthorized,
+			Body:       `{"status":"error","code":"apiKeyInvalid","message":"bad key"}`,
+		},
+		"Custom classifier": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				Classifier: func(statusCode int, _ *Error, _ error) bool {
+					return statusCode == http.StatusUnauthorized
+				},
+			},
+			Resps: []httpmock.Responder{
+				invalidKey,
+				success,
+			},
+			Calls:      2,
+			StatusCode: http.StatusOK,
+			Body:       `{"status":"ok"}`,
+		},
+		"Retry-After exceeds max backoff": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				MaxBackoff:  time.Second,
+			},
+			Resps: []httpmock.Responder{
+				func(*http.Request) (*http.Response, error) {
+					resp := httpmock.NewStringResponse(
+						http.StatusTooManyRequests,
+						`{"status":"error","code":"rateLimited","message":"slow down"}`,
+					)
+					resp.Header.Set("Retry-After", "3600")
+
+					return resp, nil
+				},
+				success,
+			},
+			Calls:      1,
+			StatusCode: http.StatusTooManyRequests,
+			Body:       `{"status":"error","code":"rateLimited","message":"slow down"}`,
+		},
+		"Retries disabled": {
+			Resps: []httpmock.Responder{
+				httpmock.NewErrorResponder(assert.AnError),
+				success,
+			},
+			Calls: 1,
+			Err:   assert.AnError,
+		},
+		"Backoff exceeds context deadline": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Hour,
+				MaxBackoff:  time.Hour,
+			},
+			Timeout: time.Minute,
+			Resps: []httpmock.Responder{
+				unexpected,
+				success,
+			},
+			Calls:      1,
+			StatusCode: http.StatusInternalServerError,
+			Body:       `{"status":"error","code":"unexpectedError","message":"bad thing"}`,
+		},
+		"Context expires during backoff": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Hour,
+				MaxBackoff:  time.Hour,
+			},
+			Timeout: 10 * time.Millisecond,
+			Resps: []httpmock.Responder{
+				httpmock.NewErrorResponder(assert.AnError),
+				success,
+			},
+			Calls: 1,
+			Err:   assert.AnError,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder(http.MethodGet, "test/123", func(req *http.Request) (*http.Response, error) {
+				calls++
+				return test.Resps[calls-1](req)
+			})
+
+			ctx := context.Background()
+			if test.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.Timeout)
+				defer cancel()
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "test/123", http.NoBody)
+			require.NoError(t, err)
+
+			hc := &http.Client{Transport: transport}
+			resp, err := test.Policy.do(req, hc.Do)
+
+			assert.Equal(t, test.Calls, calls)
+
+			if test.Err != nil {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.StatusCode, resp.StatusCode)
+			assert.Equal(t, test.Body, string(body))
+		})
+	}
+}
+
+func Test_RetryPolicy_call(t *testing.T) {
+	tests := map[string]struct {
+		Policy  RetryPolicy
+		Timeout time.Duration
+		Errs    []error
+		Calls   int
+		Err     error
+	}{
+		"Succeeds after retries": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+			},
+			Errs:  []error{assert.AnError, &Error{HTTPCode: http.StatusInternalServerError}, nil},
+			Calls: 3,
+		},
+		"Attempts exhausted": {
+			Policy: RetryPolicy{
+				MaxAttempts: 2,
+				MinBackoff:  time.Millisecond,
+			},
+			Errs:  []error{assert.AnError, assert.AnError, nil},
+			Calls: 2,
+			Err:   assert.AnError,
+		},
+		"Final api error": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+			},
+			Errs:  []error{&Error{HTTPCode: http.StatusUnauthorized, APICode: APICodeAPIKeyInvalid}, nil},
+			Calls: 1,
+			Err:   ErrAPIKeyInvalid,
+		},
+		"Validation error": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+			},
+			Errs:  []error{&ValidationError{Fields: []FieldError{{Err: ErrParamsScopeTooBroad}}}, nil},
+			Calls: 1,
+			Err:   ErrParamsScopeTooBroad,
+		},
+		"Retry after exceeds maximum backoff": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				MaxBackoff:  time.Second,
+			},
+			Errs: []error{&Error{
+				HTTPCode:   http.StatusTooManyRequests,
+				APICode:    APICodeRateLimited,
+				RetryAfter: time.Minute,
+			}, nil},
+			Calls: 1,
+			Err:   ErrRateLimited,
+		},
+		"Context expires before next attempt": {
+			Policy: RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Hour,
+				MaxBackoff:  time.Hour,
+			},
+			Timeout: time.Minute,
+			Errs:    []error{assert.AnError, nil},
+			Calls:   1,
+			Err:     assert.AnError,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if test.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.Timeout)
+				defer cancel()
+			}
+
+			var calls int
+
+			err := test.Policy.call(ctx, func(ctx context.Context) error {
+				calls++
+				return test.Errs[calls-1]
+			})
+
+			assert.Equal(t, test.Calls, calls)
+			assert.ErrorIs(t, err, test.Err)
+		})
+	}
+}
+
+func Test_sleep(t *testing.T) {
+	assert.NoError(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, sleep(ctx, time.Hour), context.Canceled)
+}
+
+func Test_RetryPolicy_backoff(t *testing.T) {
+	rp := RetryPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+
+	assert.Equal(t, time.Second, rp.backoff(1))
+	assert.Equal(t, 2*time.Second, rp.backoff(2))
+	assert.Equal(t, 4*time.Second, rp.backoff(3))
+	assert.Equal(t, 5*time.Second, rp.backoff(4))
+	assert.Equal(t, 5*time.Second, rp.backoff(100))
+
+	rp = RetryPolicy{}
+	assert.Equal(t, _defaultRetryMinBackoff, rp.backoff(1))
+	assert.Equal(t, _defaultRetryMaxBackoff, rp.backoff(100))
+
+	rp = RetryPolicy{
+		MinBackoff: time.Second,
+		Jitter:     2,
+	}
+
+	for i := 0; i < 100; i++ {
+		delay := rp.backoff(1)
+		assert.True(t, delay >= 0 && delay <= time.Second)
+	}
+}
+
+func Test_Client_get_retry(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	client := NewClient(
+		"777",
+		WithBaseURL("test/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 2,
+			MinBackoff:  time.Millisecond,
+		}),
+	)
+
+	transport.RegisterResponder(
+		http.MethodGet,
+		"test/top-headlines/sources",
+		httpmock.NewStringResponder(
+			http.StatusTooManyRequests,
+			`{"status":"error","code":"rateLimited","message":"slow down"}`,
+		),
+	)
+
+	_, err := client.Sources(context.Background(), SourceParams{})
+	assert.Equal(t, &Error{
+		HTTPCode: http.StatusTooManyRequests,
+		APICode:  "rateLimited",
+		Message:  "slow down",
+	}, err)
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
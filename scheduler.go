@@ -0,0 +1,461 @@
+package newsapi
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// _defaultSchedulerConcurrency is the number of jobs run
+	// simultaneously, unless specified otherwise.
+	_defaultSchedulerConcurrency = 4
+
+	// _defaultJobFreshness is the desired maximum age of job results,
+	// unless the job specifies it.
+	_defaultJobFreshness = time.Hour
+)
+
+// ScheduledJob is a search run periodically by the scheduler.
+type ScheduledJob struct {
+	// ID specifies the unique identifier of the job.
+	ID string
+
+	// Everything specifies everything endpoint parameters of the job.
+	// Either it or TopHeadlines must be set.
+	Everything *EverythingParams
+
+	// TopHeadlines specifies top headlines endpoint parameters of the
+	// job. Either it or Everything must be set.
+	TopHeadlines *TopHeadlinesParams
+
+	// Priority specifies the importance of the job. Jobs with higher
+	// priority are run first and have the daily quota reserved for
+	// them.
+	Priority int
+
+	// Freshness specifies the desired maximum age of the job results,
+	// i.e. how often the job is run. 1 hour is default.
+	Freshness time.Duration
+
+	// Handle is called with the retrieved articles of every successful
+	// run. The returned error is recorded as the outcome of the run.
+	Handle func(ctx context.Context, articles []Article) error
+}
+
+// freshness returns the job freshness or the default one.
+func (sj ScheduledJob) freshness() time.Duration {
+	if sj.Freshness <= 0 {
+		return _defaultJobFreshness
+	}
+
+	return sj.Freshness
+}
+
+// JobRun contains the outcome of a single job run.
+type JobRun struct {
+	// Started specifies when the run was started.
+	Started time.Time
+
+	// Finished specifies when the run was finished.
+	Finished time.Time
+
+	// Results specifies the number of retrieved articles.
+	Results int
+
+	// Err specifies the error the run failed with, if any.
+	Err error
+}
+
+// ScheduledJobStatus contains the current state of a scheduled job.
+type ScheduledJobStatus struct {
+	// ID specifies the identifier of the job.
+	ID string
+
+	// Priority specifies the priority of the job.
+	Priority int
+
+	// Running specifies whether the job is being run.
+	Running bool
+
+	// NextRun specifies when the job is due next. The job may be run
+	// later, if the concurrency or the quota does not allow it.
+	NextRun time.Time
+
+	// LastRun specifies the outcome of the last finished run. It is nil
+	// if the job has not finished any runs yet.
+	LastRun *JobRun
+}
+
+// SchedulerOption is used to apply optional scheduler configuration.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerConcurrency sets the number of jobs that can be run
+// simultaneously. 4 is default.
+func WithSchedulerConcurrency(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
+
+// WithSchedulerBudget sets the daily quota of job runs. Every run spends
+// a single request. The remaining quota is allocated by priority: a job
+// is run only if enough quota is left for the expected runs of all jobs
+// with higher priority until the budget is reset. The Wait field of the
+// budget is ignored.
+//
+// The counter should not be shared with the daily budget of the client,
+// otherwise every request is counted twice.
+func WithSchedulerBudget(db DailyBudget) SchedulerOption {
+	return func(s *Scheduler) {
+		if db.Counter == nil {
+			db.Counter = NewMemoryCounter()
+		}
+
+		s.budget = &budget{DailyBudget: db}
+	}
+}
+
+// WithSchedulerClock sets the clock used to schedule jobs. System clock
+// is default.
+func WithSchedulerClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// Scheduler runs many jobs periodically through a single API, with
+// bounded concurrency and a shared daily quota. Due jobs are run in the
+// order of their priority.
+type Scheduler struct {
+	api         API
+	concurrency int
+	budget      *budget
+	clock       Clock
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	running int
+	wake    chan struct{}
+}
+
+// scheduledJob holds the state of a scheduled job.
+type scheduledJob struct {
+	ScheduledJob
+
+	running bool
+	next    time.Time
+	last    *JobRun
+}
+
+// NewScheduler creates a fresh instance of scheduler.
+func NewScheduler(api API, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		api:         api,
+		concurrency: _defaultSchedulerConcurrency,
+		clock:       systemClock{},
+		jobs:        make(map[string]*scheduledJob),
+		wake:        make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add validates the job and schedules it to be run immediately.
+func (s *Scheduler) Add(job ScheduledJob) error {
+	if (job.Everything == nil) == (job.TopHeadlines == nil) {
+		return ErrInvalidJobParams
+	}
+
+	var err error
+	if job.Everything != nil {
+		err = job.Everything.Validate()
+	} else {
+		err = job.TopHeadlines.Validate()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.ID]; ok {
+		return ErrDuplicateJob
+	}
+
+	s.jobs[job.ID] = &scheduledJob{ScheduledJob: job}
+	s.notify()
+
+	return nil
+}
+
+// Remove unschedules the job. A run in progress is not interrupted.
+func (s *Scheduler) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	s.notify()
+}
+
+// QueueDepth returns the number of jobs that are due but not running.
+func (s *Scheduler) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.due(s.clock.Now()))
+}
+
+// Status returns the state of every scheduled job, ordered by priority
+// and id.
+func (s *Scheduler) Status() []ScheduledJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]ScheduledJobStatus, 0, len(s.jobs))
+
+	for _, sj := range s.jobs {
+		var last *JobRun
+		if sj.last != nil {
+			run := *sj.last
+			last = &run
+		}
+
+		res = append(res, ScheduledJobStatus{
+			ID:       sj.ID,
+			Priority: sj.Priority,
+			Running:  sj.running,
+			NextRun:  sj.next,
+			LastRun:  last,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Priority != res[j].Priority {
+			return res[i].Priority > res[j].Priority
+		}
+
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// Run runs due jobs until the context is cancelled. Context cancellation
+// is not treated as an error; runs in progress are waited for before
+// returning. An error is returned only when the budget counter fails.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		wait, ok, err := s.dispatch(ctx, &wg)
+		if err != nil {
+			return err
+		}
+
+		var timer <-chan time.Time
+		if ok {
+			timer = s.clock.After(wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.wake:
+		case <-timer:
+		}
+	}
+}
+
+// dispatch starts due jobs while the concurrency and the quota allow it.
+// The time to wait until another job may be started is returned; false
+// is returned when only a finished run can allow it.
+func (s *Scheduler) dispatch(ctx context.Context, wg *sync.WaitGroup) (time.Duration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	blocked := false
+
+	for _, sj := range s.due(now) {
+		if s.running >= s.concurrency {
+			return 0, false, nil
+		}
+
+		ok, err := s.take(sj, now)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if !ok {
+			blocked = true
+			continue
+		}
+
+		sj.running = true
+		sj.next = now.Add(sj.freshness())
+		s.running++
+
+		wg.Add(1)
+
+		go func(sj *scheduledJob) {
+			defer wg.Done()
+			s.run(ctx, sj, now)
+		}(sj)
+	}
+
+	if s.running >= s.concurrency {
+		return 0, false, nil
+	}
+
+	var next time.Time
+
+	if blocked {
+		next = s.reset(now)
+	}
+
+	for _, sj := range s.jobs {
+		if !sj.running && sj.next.After(now) && (next.IsZero() || sj.next.Before(next)) {
+			next = sj.next
+		}
+	}
+
+	if next.IsZero() {
+		return 0, false, nil
+	}
+
+	return next.Sub(now), true, nil
+}
+
+// due returns the jobs that are due but not running, ordered by
+// priority and due time.
+func (s *Scheduler) due(now time.Time) []*scheduledJob {
+	var res []*scheduledJob
+
+	for _, sj := range s.jobs {
+		if !sj.running && !sj.next.After(now) {
+			res = append(res, sj)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		switch {
+		case res[i].Priority != res[j].Priority:
+			return res[i].Priority > res[j].Priority
+		case !res[i].next.Equal(res[j].next):
+			return res[i].next.Before(res[j].next)
+		}
+
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// take spends a single request of the quota for the job, unless the
+// remaining quota is reserved for jobs with higher priority.
+func (s *Scheduler) take(sj *scheduledJob, now time.Time) (bool, error) {
+	if s.budget == nil {
+		return true, nil
+	}
+
+	period := s.budget.period(now)
+
+	spent, err := s.budget.Counter.Count(period)
+	if err != nil {
+		return false, err
+	}
+
+	if spent >= s.budget.Limit {
+		return false, nil
+	}
+
+	if float64(s.budget.Limit-spent) < s.reserved(sj.Priority, now)+1 {
+		return false, nil
+	}
+
+	return s.budget.Counter.Take(period, s.budget.Limit)
+}
+
+// reserved returns the number of requests the jobs with higher priority
+// than the specified one are expected to spend until the budget is
+// reset.
+func (s *Scheduler) reserved(priority int, now time.Time) float64 {
+	reset := s.reset(now)
+
+	var res float64
+
+	for _, sj := range s.jobs {
+		if sj.Priority <= priority {
+			continue
+		}
+
+		next := sj.next
+		if next.Before(now) {
+			next = now
+		}
+
+		if !next.Before(reset) {
+			continue
+		}
+
+		res += 1 + math.Floor(float64(reset.Sub(next))/float64(sj.freshness()))
+	}
+
+	return res
+}
+
+// reset returns the time at which the budget is reset next.
+func (s *Scheduler) reset(now time.Time) time.Time {
+	return s.budget.period(now).Add(_budgetPeriod)
+}
+
+// run runs the job and records its outcome.
+func (s *Scheduler) run(ctx context.Context, sj *scheduledJob, started time.Time) {
+	var (
+		articles []Article
+		err      error
+	)
+
+	if sj.Everything != nil {
+		articles, _, err = s.api.Everything(ctx, *sj.Everything)
+	} else {
+		articles, _, err = s.api.TopHeadlines(ctx, *sj.TopHeadlines)
+	}
+
+	if err == nil && sj.Handle != nil {
+		err = sj.Handle(ctx, articles)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sj.running = false
+	sj.last = &JobRun{
+		Started:  started,
+		Finished: s.clock.Now(),
+		Results:  len(articles),
+		Err:      err,
+	}
+	s.running--
+	s.notify()
+}
+
+// notify wakes up the run loop.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,253 @@
+package newsapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingAPI blocks every call until it is released.
+type blockingAPI struct {
+	stubAPI
+
+	started chan string
+	release chan struct{}
+}
+
+func (b *blockingAPI) Everything(ctx context.Context, pr EverythingParams) ([]Article, uint, error) {
+	b.started <- pr.Query
+
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+
+	return nil, 0, nil
+}
+
+func Test_Scheduler_Add(t *testing.T) {
+	s := NewScheduler(&stubAPI{})
+
+	assert.Equal(t, ErrInvalidJobParams, s.Add(ScheduledJob{ID: "1"}))
+	assert.Equal(t, ErrInvalidJobParams, s.Add(ScheduledJob{
+		ID:           "1",
+		Everything:   &EverythingParams{Query: "test"},
+		TopHeadlines: &TopHeadlinesParams{Query: "test"},
+	}))
+	assert.ErrorIs(t, s.Add(ScheduledJob{
+		ID:         "1",
+		Everything: &EverythingParams{},
+	}), ErrParamsScopeTooBroad)
+	assert.ErrorIs(t, s.Add(ScheduledJob{
+		ID:           "1",
+		TopHeadlines: &TopHeadlinesParams{},
+	}), ErrParamsScopeTooBroad)
+
+	require.NoError(t, s.Add(ScheduledJob{
+		ID:         "1",
+		Everything: &EverythingParams{Query: "test"},
+	}))
+	assert.Equal(t, ErrDuplicateJob, s.Add(ScheduledJob{
+		ID:           "1",
+		TopHeadlines: &TopHeadlinesParams{Query: "test"},
+	}))
+	assert.Equal(t, 1, s.QueueDepth())
+
+	s.Remove("1")
+	assert.Equal(t, 0, s.QueueDepth())
+	assert.Empty(t, s.Status())
+}
+
+func Test_Scheduler_Run(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{now: start}
+	api := &pollAPI{
+		cancel: cancel,
+		responses: [][]Article{
+			{testArticle("1", start)},
+			{},
+			{},
+			{},
+			{testArticle("2", start)},
+		},
+	}
+	s := NewScheduler(api, WithSchedulerConcurrency(1), WithSchedulerClock(clock))
+
+	var handled []int
+
+	require.NoError(t, s.Add(ScheduledJob{
+		ID:         "low",
+		Everything: &EverythingParams{Query: "low"},
+		Handle: func(_ context.Context, articles []Article) error {
+			handled = append(handled, len(articles))
+			return assert.AnError
+		},
+	}))
+	require.NoError(t, s.Add(ScheduledJob{
+		ID:           "high",
+		TopHeadlines: &TopHeadlinesParams{Query: "high"},
+		Priority:     10,
+		Freshness:    30 * time.Minute,
+	}))
+
+	require.NoError(t, s.Run(ctx))
+
+	require.Len(t, api.topHeadlines, 3)
+	require.Len(t, api.everything, 2)
+	assert.Equal(t, []time.Duration{30 * time.Minute, 30 * time.Minute}, clock.waits)
+	assert.Equal(t, []int{0, 1}, handled)
+
+	assert.Equal(t, []ScheduledJobStatus{
+		{
+			ID:       "high",
+			Priority: 10,
+			NextRun:  start.Add(90 * time.Minute),
+			LastRun: &JobRun{
+				Started:  start.Add(time.Hour),
+				Finished: start.Add(time.Hour),
+			},
+		},
+		{
+			ID:      "low",
+			NextRun: start.Add(2 * time.Hour),
+			LastRun: &JobRun{
+				Started:  start.Add(time.Hour),
+				Finished: start.Add(time.Hour),
+				Results:  1,
+				Err:      assert.AnError,
+			},
+		},
+	}, s.Status())
+}
+
+func Test_Scheduler_Run_concurrency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &blockingAPI{
+		started: make(chan string),
+		release: make(chan struct{}),
+	}
+	s := NewScheduler(api, WithSchedulerConcurrency(2))
+
+	var (
+		mu      sync.Mutex
+		handled int
+	)
+
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		require.NoError(t, s.Add(ScheduledJob{
+			ID:         id,
+			Everything: &EverythingParams{Query: id},
+			Handle: func(context.Context, []Article) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				handled++
+				if handled == 5 {
+					cancel()
+				}
+
+				return nil
+			},
+		}))
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- s.Run(ctx)
+	}()
+
+	var started []string
+
+	for i := 0; i < 5; i++ {
+		started = append(started, <-api.started)
+
+		if i%2 == 1 {
+			assert.Equal(t, 5-len(started), s.QueueDepth())
+			api.release <- struct{}{}
+			api.release <- struct{}{}
+		}
+	}
+
+	api.release <- struct{}{}
+
+	require.NoError(t, <-errs)
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4", "5"}, started)
+	assert.Equal(t, 0, s.QueueDepth())
+
+	for _, st := range s.Status() {
+		assert.False(t, st.Running)
+		require.NotNil(t, st.LastRun)
+		assert.NoError(t, st.LastRun.Err)
+	}
+}
+
+func Test_Scheduler_take(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	counter := NewMemoryCounter()
+
+	s := NewScheduler(&stubAPI{},
+		WithSchedulerBudget(DailyBudget{Limit: 6, Counter: counter}),
+		WithSchedulerClock(&fakeClock{now: start}),
+	)
+
+	for _, job := range []ScheduledJob{
+		{ID: "high", Priority: 10, Freshness: 6 * time.Hour},
+		{ID: "low1"},
+		{ID: "low2"},
+	} {
+		job.Everything = &EverythingParams{Query: job.ID}
+		require.NoError(t, s.Add(job))
+	}
+
+	// 5 requests are reserved for the high priority job until the reset.
+	ok, err := s.take(s.jobs["low1"], start)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = s.take(s.jobs["low2"], start)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.take(s.jobs["high"], start)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Fewer requests are reserved closer to the reset.
+	s.jobs["high"].next = start.Add(18 * time.Hour)
+
+	ok, err = s.take(s.jobs["low2"], start.Add(18*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	spent, err := counter.Count(start)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), spent)
+
+	// Exhausted budget is not spent any further.
+	for i := 0; i < 3; i++ {
+		ok, err = s.take(s.jobs["high"], start)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	ok, err = s.take(s.jobs["high"], start)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Quota is available again after the reset.
+	ok, err = s.take(s.jobs["low1"], start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
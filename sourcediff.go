@@ -0,0 +1,170 @@
+package newsapi
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SourceDiff contains changes between two snapshots of sources.
+type SourceDiff struct {
+	// Added specifies the sources that appear only in the newer
+	// snapshot, ordered by id.
+	Added []Source `json:"added"`
+
+	// Removed specifies the sources that appear only in the older
+	// snapshot, ordered by id.
+	Removed []Source `json:"removed"`
+
+	// Modified specifies the sources whose fields changed, ordered by
+	// id.
+	Modified []SourceChange `json:"modified"`
+}
+
+// SourceChange contains the changed fields of a single source.
+type SourceChange struct {
+	// ID specifies the identifier of the source.
+	ID string `json:"id"`
+
+	// Name specifies the name of the source in the newer snapshot.
+	Name string `json:"name"`
+
+	// Fields specifies the changed fields, in the order of Source
+	// fields.
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange contains the values of a changed field.
+type FieldChange struct {
+	// Field specifies the name of the field, e.g. "URL".
+	Field string `json:"field"`
+
+	// Old specifies the value in the older snapshot.
+	Old string `json:"old"`
+
+	// New specifies the value in the newer snapshot.
+	New string `json:"new"`
+}
+
+// DiffSources compares the snapshot of sources taken before with the one
+// taken after, matching the sources by id. Changes of name, description,
+// url, category, language and country are reported.
+func DiffSources(before, after []Source) *SourceDiff {
+	beforeByID := make(map[string]Source, len(before))
+	for _, src := range before {
+		beforeByID[src.ID] = src
+	}
+
+	afterByID := make(map[string]Source, len(after))
+	for _, src := range after {
+		afterByID[src.ID] = src
+	}
+
+	diff := &SourceDiff{
+		Added:    []Source{},
+		Removed:  []Source{},
+		Modified: []SourceChange{},
+	}
+
+	for id, src := range afterByID {
+		prev, ok := beforeByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, src)
+			continue
+		}
+
+		if fields := diffSource(prev, src); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, SourceChange{
+				ID:     id,
+				Name:   src.Name,
+				Fields: fields,
+			})
+		}
+	}
+
+	for id, src := range beforeByID {
+		if _, ok := afterByID[id]; !ok {
+			diff.Removed = append(diff.Removed, src)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool {
+		return diff.Added[i].ID < diff.Added[j].ID
+	})
+
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		return diff.Removed[i].ID < diff.Removed[j].ID
+	})
+
+	sort.Slice(diff.Modified, func(i, j int) bool {
+		return diff.Modified[i].ID < diff.Modified[j].ID
+	})
+
+	return diff
+}
+
+// Empty checks if there are no changes.
+func (sd *SourceDiff) Empty() bool {
+	return len(sd.Added) == 0 && len(sd.Removed) == 0 && len(sd.Modified) == 0
+}
+
+// WriteText renders the changes in a human readable format, one source
+// per line, prefixed with "+" if added, "-" if removed and "~" if
+// modified. Changed fields are listed below modified sources.
+func (sd *SourceDiff) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	for _, src := range sd.Added {
+		fmt.Fprintf(&b, "+ %s\n", describeSource(src))
+	}
+
+	for _, src := range sd.Removed {
+		fmt.Fprintf(&b, "- %s\n", describeSource(src))
+	}
+
+	for _, sc := range sd.Modified {
+		fmt.Fprintf(&b, "~ %s (%s)\n", sc.ID, sc.Name)
+
+		for _, fc := range sc.Fields {
+			fmt.Fprintf(&b, "    %s: %q -> %q\n", fc.Field, fc.Old, fc.New)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// String renders the changes in a human readable format.
+func (sd *SourceDiff) String() string {
+	var b strings.Builder
+	_ = sd.WriteText(&b)
+
+	return b.String()
+}
+
+// diffSource returns the changed fields of the source.
+func diffSource(before, after Source) []FieldChange {
+	var res []FieldChange
+
+	for _, fc := range []FieldChange{
+		{Field: "Name", Old: before.Name, New: after.Name},
+		{Field: "Description", Old: before.Description, New: after.Description},
+		{Field: "URL", Old: before.URL, New: after.URL},
+		{Field: "Category", Old: string(before.Category), New: string(after.Category)},
+		{Field: "Language", Old: string(before.Language), New: string(after.Language)},
+		{Field: "Country", Old: string(before.Country), New: string(after.Country)},
+	} {
+		if fc.Old != fc.New {
+			res = append(res, fc)
+		}
+	}
+
+	return res
+}
+
+// describeSource returns a single line description of the source.
+func describeSource(src Source) string {
+	return fmt.Sprintf("%s (%s) %s %s/%s/%s", src.ID, src.Name, src.URL, src.Category, src.Language, src.Country)
+}
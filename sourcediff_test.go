@@ -0,0 +1,98 @@
+package newsapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffSources(t *testing.T) {
+	before := testCatalogSources()
+	after := testCatalogSources()[1:]
+
+	after[0].URL = "https://www.bbc.com/sport"
+	after[0].Description = "Sport news"
+	after[2].Language = LanguageEnglish
+	after[2].Name = "Tagesspiegel"
+	after = append(after, Source{
+		SourceID: SourceID{ID: "abc-news", Name: "ABC News"},
+		URL:      "https://abcnews.go.com",
+		Category: CategoryGeneral,
+		Language: LanguageEnglish,
+		Country:  CountryUnitedStates,
+	})
+
+	diff := DiffSources(before, after)
+	assert.Equal(t, &SourceDiff{
+		Added:   []Source{after[4]},
+		Removed: []Source{before[0]},
+		Modified: []SourceChange{
+			{
+				ID:   "bbc-sport",
+				Name: "BBC Sport",
+				Fields: []FieldChange{
+					{Field: "Description", Old: "", New: "Sport news"},
+					{Field: "URL", Old: "http://www.bbc.co.uk/sport", New: "https://www.bbc.com/sport"},
+				},
+			},
+			{
+				ID:   "der-tagesspiegel",
+				Name: "Tagesspiegel",
+				Fields: []FieldChange{
+					{Field: "Name", Old: "Der Tagesspiegel", New: "Tagesspiegel"},
+					{Field: "Language", Old: "de", New: "en"},
+				},
+			},
+		},
+	}, diff)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, DiffSources(before, testCatalogSources()).Empty())
+	assert.True(t, DiffSources(nil, nil).Empty())
+}
+
+func Test_SourceDiff_WriteText(t *testing.T) {
+	before := testCatalogSources()[:2]
+	after := testCatalogSources()[1:3]
+	after[0].Country = CountryIreland
+
+	assert.Equal(t, ""+
+		"+ cnn (CNN) http://us.cnn.com general/en/us\n"+
+		"- bbc-news (BBC News) http://www.bbc.co.uk/news general/en/gb\n"+
+		"~ bbc-sport (BBC Sport)\n"+
+		"    Country: \"gb\" -> \"ie\"\n",
+		DiffSources(before, after).String(),
+	)
+
+	assert.Equal(t, "", DiffSources(before, before).String())
+}
+
+func Test_SourceDiff_JSON(t *testing.T) {
+	diff := DiffSources(testCatalogSources()[:1], nil)
+
+	data, err := json.Marshal(diff)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"added": [],
+		"removed": [{
+			"id": "bbc-news",
+			"name": "BBC News",
+			"description": "",
+			"url": "http://www.bbc.co.uk/news",
+			"category": "general",
+			"language": "en",
+			"country": "gb"
+		}],
+		"modified": []
+	}`, string(data))
+
+	var res SourceDiff
+	require.NoError(t, json.Unmarshal(data, &res))
+	assert.Equal(t, diff, &res)
+
+	data, err = json.Marshal(DiffSources(nil, nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"added": [], "removed": [], "modified": []}`, string(data))
+}
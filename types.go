@@ -4,6 +4,18 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	"github.com/jellydator/newsapi-go/query"
+)
+
+const (
+	// _maxQueryLength is the maximum number of characters newsapi
+	// accepts in a query.
+	_maxQueryLength = 500
+
+	// _maxSources is the maximum number of sources newsapi accepts in a
+	// single request.
+	_maxSources = 20
 )
 
 // All available sort keys.
@@ -312,7 +324,11 @@ type Article struct {
 	Content string `json:"content"`
 }
 
-// SourceParams contains source endpoint filters.
+// SourceParams contains source endpoint filters. Newsapi honors only a
+// single value of every filter, so when any filter has multiple values,
+// Client.Sources sends a separate request for every combination of the
+// values. Every filter is applied by newsapi; none of them are applied
+// locally.
 type SourceParams struct {
 	// Categories is used to filter sources by categories. If left empty
 	// all categories are used.
@@ -327,27 +343,166 @@ type SourceParams struct {
 	Countries []Country
 }
 
-// validate validates parameters and their compatibility.
-func (sr *SourceParams) validate() error {
+// Validate validates parameters and their compatibility. All failures
+// are reported as *ValidationError, which matches the sentinel errors of
+// the failing fields, e.g. ErrInvalidCategory.
+func (sr *SourceParams) Validate() error {
+	var ve ValidationError
+
 	for _, category := range sr.Categories {
 		if !category.isValid() {
-			return ErrInvalidCategory
+			ve.add("Categories", category, ErrInvalidCategory)
 		}
 	}
 
 	for _, language := range sr.Languages {
 		if !language.isValid() {
-			return ErrInvalidLanguage
+			ve.add("Languages", language, ErrInvalidLanguage)
 		}
 	}
 
 	for _, country := range sr.Countries {
 		if !country.isValid() {
-			return ErrInvalidCountry
+			ve.add("Countries", country, ErrInvalidCountry)
 		}
 	}
 
-	return nil
+	return ve.err()
+}
+
+// expand returns the parameters of every combination of the distinct
+// filter values, with at most a single value of every filter.
+func (sr *SourceParams) expand() []SourceParams {
+	res := []SourceParams{{}}
+
+	if len(sr.Categories) > 0 {
+		var next []SourceParams
+
+		categories := distinctCategories(sr.Categories)
+
+		for _, pr := range res {
+			for _, category := range categories {
+				pr.Categories = []Category{category}
+				next = append(next, pr)
+			}
+		}
+
+		res = next
+	}
+
+	if len(sr.Languages) > 0 {
+		var next []SourceParams
+
+		languages := distinctLanguages(sr.Languages)
+
+		for _, pr := range res {
+			for _, language := range languages {
+				pr.Languages = []Language{language}
+				next = append(next, pr)
+			}
+		}
+
+		res = next
+	}
+
+	if len(sr.Countries) > 0 {
+		var next []SourceParams
+
+		countries := distinctCountries(sr.Countries)
+
+		for _, pr := range res {
+			for _, country := range countries {
+				pr.Countries = []Country{country}
+				next = append(next, pr)
+			}
+		}
+
+		res = next
+	}
+
+	return res
+}
+
+// distinctCategories returns the categories without repeated values,
+// in the order of their first occurrence.
+func distinctCategories(categories []Category) []Category {
+	res := make([]Category, 0, len(categories))
+	seen := make(map[Category]struct{}, len(categories))
+
+	for _, category := range categories {
+		if _, ok := seen[category]; ok {
+			continue
+		}
+
+		seen[category] = struct{}{}
+		res = append(res, category)
+	}
+
+	return res
+}
+
+// distinctLanguages returns the languages without repeated values, in
+// the order of their first occurrence.
+func distinctLanguages(languages []Language) []Language {
+	res := make([]Language, 0, len(languages))
+	seen := make(map[Language]struct{}, len(languages))
+
+	for _, language := range languages {
+		if _, ok := seen[language]; ok {
+			continue
+		}
+
+		seen[language] = struct{}{}
+		res = append(res, language)
+	}
+
+	return res
+}
+
+// distinctCountries returns the countries without repeated values, in
+// the order of their first occurrence.
+func distinctCountries(countries []Country) []Country {
+	res := make([]Country, 0, len(countries))
+	seen := make(map[Country]struct{}, len(countries))
+
+	for _, country := range countries {
+		if _, ok := seen[country]; ok {
+			continue
+		}
+
+		seen[country] = struct{}{}
+		res = append(res, country)
+	}
+
+	return res
+}
+
+// match checks if the source matches one of the values of every filter.
+func (sr *SourceParams) match(src Source) bool {
+	matched := len(sr.Categories) == 0
+	for _, category := range sr.Categories {
+		matched = matched || category == src.Category
+	}
+
+	if !matched {
+		return false
+	}
+
+	matched = len(sr.Languages) == 0
+	for _, language := range sr.Languages {
+		matched = matched || language == src.Language
+	}
+
+	if !matched {
+		return false
+	}
+
+	matched = len(sr.Countries) == 0
+	for _, country := range sr.Countries {
+		matched = matched || country == src.Country
+	}
+
+	return matched
 }
 
 // rawQuery constructs a raw query from parameters.
@@ -404,32 +559,36 @@ type TopHeadlinesParams struct {
 	Page uint
 }
 
-// validate validates parameters and their compatibility.
-func (thp *TopHeadlinesParams) validate() error {
-	if len(thp.Query) > 500 {
-		return ErrInvalidQueryLength
+// Validate validates parameters and their compatibility. All failures
+// are reported as *ValidationError, which matches the sentinel errors of
+// the failing fields, e.g. ErrInvalidLanguage.
+func (thp *TopHeadlinesParams) Validate() error {
+	var ve ValidationError
+
+	if len(thp.Query) > _maxQueryLength {
+		ve.add("Query", thp.Query, ErrInvalidQueryLength)
 	}
 
 	if thp.Category != "" && !thp.Category.isValid() {
-		return ErrInvalidCategory
+		ve.add("Category", thp.Category, ErrInvalidCategory)
 	}
 
 	if thp.Language != "" && !thp.Language.isValid() {
-		return ErrInvalidLanguage
+		ve.add("Language", thp.Language, ErrInvalidLanguage)
 	}
 
 	if thp.Country != "" && !thp.Country.isValid() {
-		return ErrInvalidCountry
+		ve.add("Country", thp.Country, ErrInvalidCountry)
 	}
 
 	if len(thp.Sources) != 0 &&
 		(thp.Country != "" || thp.Category != "") {
 
-		return ErrIncompatibleParams
+		ve.add("Sources", thp.Sources, ErrIncompatibleParams)
 	}
 
 	if thp.PageSize > 100 {
-		return ErrInvalidPageSize
+		ve.add("PageSize", thp.PageSize, ErrInvalidPageSize)
 	}
 
 	if thp.Query == "" &&
@@ -438,10 +597,10 @@ func (thp *TopHeadlinesParams) validate() error {
 		thp.Country == "" &&
 		len(thp.Sources) == 0 {
 
-		return ErrParamsScopeTooBroad
+		ve.add("", nil, ErrParamsScopeTooBroad)
 	}
 
-	return nil
+	return ve.err()
 }
 
 // rawQuery constructsa a raw query from parameters.
@@ -497,6 +656,11 @@ type EverythingParams struct {
 	// Query has a maximum length of 500 characters.
 	Query string
 
+	// StrictQuery specifies whether the query should be parsed before
+	// sending the request, so that syntax errors are reported as
+	// *query.SyntaxError instead of newsapi errors.
+	StrictQuery bool
+
 	// QueryInTitle is used to filter article title. Unlike query
 	// parameter it doesn't allow for an advanced search, so only basic
 	// keywords or phrases should be used.
@@ -541,34 +705,45 @@ type EverythingParams struct {
 	Page uint
 }
 
-// validate validates parameters and their compatibility.
-func (ep *EverythingParams) validate() error {
-	if len(ep.Query) > 500 {
-		return ErrInvalidQueryLength
+// Validate validates parameters and their compatibility. All failures
+// are reported as *ValidationError, which matches the sentinel errors of
+// the failing fields, e.g. ErrInvalidLanguage. Query syntax errors are
+// reported as *query.SyntaxError field errors.
+func (ep *EverythingParams) Validate() error {
+	var ve ValidationError
+
+	if len(ep.Query) > _maxQueryLength {
+		ve.add("Query", ep.Query, ErrInvalidQueryLength)
+	}
+
+	if ep.StrictQuery && ep.Query != "" {
+		if _, err := query.Parse(ep.Query); err != nil {
+			ve.add("Query", ep.Query, err)
+		}
 	}
 
 	if ep.SearchIn != "" && !ep.SearchIn.isValid() {
-		return ErrInvalidSearchIn
+		ve.add("SearchIn", ep.SearchIn, ErrInvalidSearchIn)
 	}
 
-	if len(ep.Sources) > 20 {
-		return ErrTooManySources
+	if len(ep.Sources) > _maxSources {
+		ve.add("Sources", ep.Sources, ErrTooManySources)
 	}
 
 	if !ep.From.IsZero() && !ep.To.IsZero() && ep.From.After(ep.To) {
-		return ErrInvalidFromTime
+		ve.add("From", ep.From, ErrInvalidFromTime)
 	}
 
 	if ep.Language != "" && !ep.Language.isValid() {
-		return ErrInvalidLanguage
+		ve.add("Language", ep.Language, ErrInvalidLanguage)
 	}
 
 	if ep.SortBy != "" && !ep.SortBy.isValid() {
-		return ErrInvalidSortBy
+		ve.add("SortBy", ep.SortBy, ErrInvalidSortBy)
 	}
 
 	if ep.PageSize > 100 {
-		return ErrInvalidPageSize
+		ve.add("PageSize", ep.PageSize, ErrInvalidPageSize)
 	}
 
 	if ep.Query == "" &&
@@ -576,10 +751,10 @@ func (ep *EverythingParams) validate() error {
 		len(ep.Sources) == 0 &&
 		len(ep.Domains) == 0 {
 
-		return ErrParamsScopeTooBroad
+		ve.add("", nil, ErrParamsScopeTooBroad)
 	}
 
-	return nil
+	return ve.err()
 }
 
 // rawQuery constructsa a raw query from parameters.
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jellydator/newsapi-go/query"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,7 +139,7 @@ func Test_Country_isValid(t *testing.T) {
 	assert.False(t, country.isValid())
 }
 
-func Test_SourceParams_validate(t *testing.T) {
+func Test_SourceParams_Validate(t *testing.T) {
 	tests := map[string]struct {
 		Params SourceParams
 		Err    error
@@ -190,11 +191,83 @@ func Test_SourceParams_validate(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			assert.Equal(t, test.Err, test.Params.validate())
+
+			err := test.Params.Validate()
+			if test.Err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.Err)
 		})
 	}
 }
 
+func Test_SourceParams_Validate_allFailures(t *testing.T) {
+	pr := SourceParams{
+		Categories: []Category{CategoryBusiness, "123"},
+		Languages:  []Language{"123", "456"},
+		Countries:  []Country{"123"},
+	}
+
+	assert.Equal(t, &ValidationError{Fields: []FieldError{
+		{Field: "Categories", Value: Category("123"), Err: ErrInvalidCategory},
+		{Field: "Languages", Value: Language("123"), Err: ErrInvalidLanguage},
+		{Field: "Languages", Value: Language("456"), Err: ErrInvalidLanguage},
+		{Field: "Countries", Value: Country("123"), Err: ErrInvalidCountry},
+	}}, pr.Validate())
+}
+
+func Test_SourceParams_expand(t *testing.T) {
+	assert.Equal(t, []SourceParams{{}}, (&SourceParams{}).expand())
+	assert.Equal(t, []SourceParams{{
+		Languages: []Language{LanguageItalian},
+	}}, (&SourceParams{
+		Languages: []Language{LanguageItalian},
+	}).expand())
+	assert.Equal(t, []SourceParams{
+		{Categories: []Category{CategoryBusiness}, Languages: []Language{LanguageItalian}, Countries: []Country{CountryArgentina}},
+		{Categories: []Category{CategoryBusiness}, Languages: []Language{LanguageSpanish}, Countries: []Country{CountryArgentina}},
+		{Categories: []Category{CategoryScience}, Languages: []Language{LanguageItalian}, Countries: []Country{CountryArgentina}},
+		{Categories: []Category{CategoryScience}, Languages: []Language{LanguageSpanish}, Countries: []Country{CountryArgentina}},
+	}, (&SourceParams{
+		Categories: []Category{CategoryBusiness, CategoryScience},
+		Languages:  []Language{LanguageItalian, LanguageSpanish},
+		Countries:  []Country{CountryArgentina},
+	}).expand())
+	assert.Equal(t, []SourceParams{
+		{Categories: []Category{CategoryBusiness}, Countries: []Country{CountryArgentina}},
+		{Categories: []Category{CategoryScience}, Countries: []Country{CountryArgentina}},
+	}, (&SourceParams{
+		Categories: []Category{CategoryBusiness, CategoryScience, CategoryBusiness},
+		Countries:  []Country{CountryArgentina, CountryArgentina},
+	}).expand())
+}
+
+func Test_SourceParams_match(t *testing.T) {
+	src := Source{
+		Category: CategoryBusiness,
+		Language: LanguageEnglish,
+		Country:  CountryUnitedStates,
+	}
+
+	assert.True(t, (&SourceParams{}).match(src))
+	assert.True(t, (&SourceParams{
+		Categories: []Category{CategoryScience, CategoryBusiness},
+		Languages:  []Language{LanguageEnglish},
+		Countries:  []Country{CountryGermany, CountryUnitedStates},
+	}).match(src))
+	assert.False(t, (&SourceParams{
+		Categories: []Category{CategoryScience},
+	}).match(src))
+	assert.False(t, (&SourceParams{
+		Languages: []Language{LanguageGerman},
+	}).match(src))
+	assert.False(t, (&SourceParams{
+		Countries: []Country{CountryGermany},
+	}).match(src))
+}
+
 func Test_SourceParams_rawQuery(t *testing.T) {
 	assert.Equal(t, "", (&SourceParams{}).rawQuery())
 	assert.Equal(
@@ -217,7 +290,7 @@ func Test_SourceParams_rawQuery(t *testing.T) {
 	)
 }
 
-func Test_TopHeadlinesParams_validate(t *testing.T) {
+func Test_TopHeadlinesParams_Validate(t *testing.T) {
 	tests := map[string]struct {
 		Params TopHeadlinesParams
 		Err    error
@@ -279,11 +352,32 @@ func Test_TopHeadlinesParams_validate(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			assert.Equal(t, test.Err, test.Params.validate())
+
+			err := test.Params.Validate()
+			if test.Err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.Err)
 		})
 	}
 }
 
+func Test_TopHeadlinesParams_Validate_allFailures(t *testing.T) {
+	pr := TopHeadlinesParams{
+		Category: Category("test"),
+		Sources:  []string{"test"},
+		PageSize: 101,
+	}
+
+	assert.Equal(t, &ValidationError{Fields: []FieldError{
+		{Field: "Category", Value: Category("test"), Err: ErrInvalidCategory},
+		{Field: "Sources", Value: []string{"test"}, Err: ErrIncompatibleParams},
+		{Field: "PageSize", Value: uint(101), Err: ErrInvalidPageSize},
+	}}, pr.Validate())
+}
+
 func Test_TopHeadlinesParams_rawQuery(t *testing.T) {
 	assert.Equal(t, "", (&TopHeadlinesParams{}).rawQuery())
 	assert.Equal(
@@ -311,7 +405,7 @@ func Test_TopHeadlinesParams_rawQuery(t *testing.T) {
 	)
 }
 
-func Test_EverythingParams_validate(t *testing.T) {
+func Test_EverythingParams_Validate(t *testing.T) {
 	tests := map[string]struct {
 		Params EverythingParams
 		Err    error
@@ -370,6 +464,17 @@ func Test_EverythingParams_validate(t *testing.T) {
 				},
 			},
 		},
+		"Valid strict query": {
+			Params: EverythingParams{
+				Query:       "crypto AND (bitcoin OR ethereum)",
+				StrictQuery: true,
+			},
+		},
+		"Invalid query syntax is not checked without strict query": {
+			Params: EverythingParams{
+				Query: "crypto AND (bitcoin",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -377,11 +482,47 @@ func Test_EverythingParams_validate(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			assert.Equal(t, test.Err, test.Params.validate())
+
+			err := test.Params.Validate()
+			if test.Err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.Err)
 		})
 	}
 }
 
+func Test_EverythingParams_Validate_allFailures(t *testing.T) {
+	pr := EverythingParams{
+		Query:       "crypto AND (bitcoin",
+		StrictQuery: true,
+		Language:    Language("test"),
+		SortBy:      SortBy("test"),
+	}
+
+	err := pr.Validate()
+	assert.Equal(t, &ValidationError{Fields: []FieldError{
+		{Field: "Query", Value: "crypto AND (bitcoin", Err: &query.SyntaxError{
+			Offset:  11,
+			Message: "unbalanced parentheses: missing closing parenthesis",
+		}},
+		{Field: "Language", Value: Language("test"), Err: ErrInvalidLanguage},
+		{Field: "SortBy", Value: SortBy("test"), Err: ErrInvalidSortBy},
+	}}, err)
+
+	var se *query.SyntaxError
+	assert.ErrorAs(t, err, &se)
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+	assert.NotErrorIs(t, err, ErrParamsScopeTooBroad)
+
+	pr = EverythingParams{}
+	assert.Equal(t, &ValidationError{Fields: []FieldError{
+		{Err: ErrParamsScopeTooBroad},
+	}}, pr.Validate())
+}
+
 func Test_EverythingParams_rawQuery(t *testing.T) {
 	tstamp := time.Date(2022, 02, 22, 22, 22, 22, 22, time.UTC)
 
@@ -0,0 +1,365 @@
+package newsapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// _defaultWatchInterval is the interval used between polls, unless the
+// watcher specifies it.
+const _defaultWatchInterval = 5 * time.Minute
+
+// Clock provides the current time and timers. It allows to control
+// time in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is a clock that uses the time package.
+type systemClock struct{}
+
+// Now returns the current local time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After calls time.After.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Watcher periodically polls either everything or top headlines endpoint
+// and emits only the articles it has not seen before, oldest first.
+// Everything endpoint From time is narrowed down to the newest article
+// seen so far on every poll. The first poll retrieves a single page of
+// articles, while the later ones page through the articles published
+// since the previous poll.
+//
+// Any poll error stops the watcher, so the API should be decorated with
+// Retrying to tolerate transient failures.
+type Watcher struct {
+	// API is used to retrieve articles.
+	API API
+
+	// Everything specifies everything endpoint parameters to poll.
+	// Either it or TopHeadlines must be set. Its SortBy is ignored, as
+	// articles are always requested newest first.
+	Everything *EverythingParams
+
+	// TopHeadlines specifies top headlines endpoint parameters to poll.
+	// Either it or Everything must be set.
+	TopHeadlines *TopHeadlinesParams
+
+	// Interval specifies the time between the starts of two consecutive
+	// polls. 5 minutes is default. When Adaptive is set, it specifies
+	// the initial interval, which is the minimum adaptive interval by
+	// default.
+	Interval time.Duration
+
+	// Adaptive specifies how the interval is adjusted to the observed
+	// article velocity. If left empty, the interval is fixed.
+	Adaptive *AdaptiveInterval
+
+	// Budget specifies the request budget shared with other watchers.
+	// The interval is prolonged whenever it does not fit into the
+	// budget. Every page retrieved by a poll counts as a request.
+	Budget *PollBudget
+
+	// Lookback specifies how far before the newest seen article the
+	// polled time window starts. Since newsapi indexes some articles
+	// with a delay, it allows to catch up on articles that appear later
+	// than newer ones. Articles older than that are ignored.
+	Lookback time.Duration
+
+	// SkipInitial specifies whether the articles retrieved by the first
+	// poll should be marked as seen without emitting them.
+	SkipInitial bool
+
+	// Clock is used to schedule polls. System clock is default.
+	Clock Clock
+}
+
+// Run polls the endpoint until the context is cancelled or an error
+// occurs. Context cancellation is not treated as an error; it aborts the
+// poll in progress, whose remaining articles are not emitted.
+func (w *Watcher) Run(ctx context.Context, emit func(Article) error) error {
+	if (w.Everything == nil) == (w.TopHeadlines == nil) {
+		return ErrInvalidWatchParams
+	}
+
+	var err error
+	if w.Everything != nil {
+		err = w.Everything.Validate()
+	} else {
+		err = w.TopHeadlines.Validate()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	wt := &watch{
+		Watcher: w,
+		emit:    emit,
+		seen:    make(map[string]time.Time),
+		initial: true,
+	}
+
+	var share *pollShare
+	if w.Budget != nil {
+		share = w.Budget.join()
+		defer share.leave()
+	}
+
+	interval := w.interval()
+
+	for {
+		start := w.clock().Now()
+		initial := wt.initial
+
+		var found, requests int
+
+		found, requests, err = wt.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		// Every article is new to the first poll, so it says nothing
+		// about the velocity.
+		if w.Adaptive != nil && !initial {
+			interval = w.Adaptive.next(interval, found)
+		}
+
+		if share != nil {
+			interval = share.limit(interval, requests)
+		}
+
+		next := start.Add(interval)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.clock().After(next.Sub(w.clock().Now())):
+		}
+	}
+}
+
+// Watch runs the watcher in a separate goroutine and delivers new
+// articles over the returned channel. The channel is closed when the
+// watcher stops; the error channel then receives the error that stopped
+// it, if any, and is closed too.
+func (w *Watcher) Watch(ctx context.Context) (<-chan Article, <-chan error) {
+	articles := make(chan Article)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(articles)
+
+		err := w.Run(ctx, func(a Article) error {
+			select {
+			case articles <- a:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return articles, errs
+}
+
+// interval returns the initial poll interval or the default one.
+func (w *Watcher) interval() time.Duration {
+	switch {
+	case w.Adaptive != nil && w.Interval <= 0:
+		return w.Adaptive.min()
+	case w.Adaptive != nil:
+		return w.Adaptive.clamp(w.Interval)
+	case w.Interval <= 0:
+		return _defaultWatchInterval
+	}
+
+	return w.Interval
+}
+
+// clock returns the clock or the system clock.
+func (w *Watcher) clock() Clock {
+	if w.Clock == nil {
+		return systemClock{}
+	}
+
+	return w.Clock
+}
+
+// watch holds the state of a single watcher run.
+type watch struct {
+	*Watcher
+
+	emit    func(Article) error
+	seen    map[string]time.Time
+	newest  time.Time
+	initial bool
+}
+
+// poll retrieves the articles and emits the ones that were not seen yet.
+// The number of new articles and the number of requests sent are
+// returned.
+func (wt *watch) poll(ctx context.Context) (int, int, error) {
+	articles, requests, err := wt.fetch(ctx)
+	if err != nil {
+		return 0, requests, err
+	}
+
+	var found int
+
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].PublishedAt.Before(articles[j].PublishedAt)
+	})
+
+	cutoff := wt.cutoff()
+
+	for _, a := range articles {
+		if !cutoff.IsZero() && a.PublishedAt.Before(cutoff) {
+			continue
+		}
+
+		fp := fingerprint(a)
+		if _, ok := wt.seen[fp]; ok {
+			continue
+		}
+
+		if !wt.initial || !wt.SkipInitial {
+			if err = wt.emit(a); err != nil {
+				return found, requests, err
+			}
+		}
+
+		found++
+		wt.seen[fp] = a.PublishedAt
+
+		if a.PublishedAt.After(wt.newest) {
+			wt.newest = a.PublishedAt
+		}
+	}
+
+	wt.initial = false
+
+	// Articles published before the cutoff are ignored, so there is no
+	// need to remember them.
+	cutoff = wt.cutoff()
+	for fp, publishedAt := range wt.seen {
+		if publishedAt.Before(cutoff) {
+			delete(wt.seen, fp)
+		}
+	}
+
+	return found, requests, nil
+}
+
+// fetch retrieves the articles of the watched endpoint. Everything
+// endpoint is sorted by publication time, with From time narrowed down
+// to the cutoff time, and is paged through, newest first, until a page
+// reaches articles that were already seen or are older than the cutoff,
+// or until the results run out. Nothing is seen before the first poll,
+// so it retrieves only the first page instead of the whole history.
+// The number of requests sent is returned along with the articles.
+func (wt *watch) fetch(ctx context.Context) ([]Article, int, error) {
+	if wt.TopHeadlines != nil {
+		articles, _, err := wt.API.TopHeadlines(ctx, *wt.TopHeadlines)
+		return articles, 1, err
+	}
+
+	pr := *wt.Everything
+	pr.Page = 1
+
+	// The cutoff relies on the newest articles coming first.
+	pr.SortBy = SortByPublishedAt
+
+	cutoff := wt.cutoff()
+	if cutoff.After(pr.From) {
+		// newsapi time filters have a resolution of one second.
+		pr.From = cutoff.Truncate(time.Second)
+	}
+
+	pageSize := pr.PageSize
+	if pageSize == 0 {
+		pageSize = _defaultPageSize
+	}
+
+	var articles []Article
+
+	for requests := 1; ; requests++ {
+		page, total, err := wt.API.Everything(ctx, pr)
+		if err != nil {
+			if errors.Is(err, ErrMaximumResultsReached) {
+				return articles, requests, nil
+			}
+
+			return nil, requests, err
+		}
+
+		articles = append(articles, page...)
+
+		if wt.initial || uint(len(page)) < pageSize || uint(len(articles)) >= total || wt.caughtUp(page, cutoff) {
+			return articles, requests, nil
+		}
+
+		pr.Page++
+	}
+}
+
+// caughtUp checks if the page contains an article that was already seen
+// or is older than the cutoff, i.e. if older pages contain no new
+// articles.
+func (wt *watch) caughtUp(page []Article, cutoff time.Time) bool {
+	for _, a := range page {
+		if !cutoff.IsZero() && a.PublishedAt.Before(cutoff) {
+			return true
+		}
+
+		if _, ok := wt.seen[fingerprint(a)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cutoff returns the time before which articles are ignored. It is zero
+// until the first article is seen.
+func (wt *watch) cutoff() time.Time {
+	if wt.newest.IsZero() {
+		return time.Time{}
+	}
+
+	return wt.newest.Add(-wt.Lookback)
+}
+
+// fingerprint returns a short hash identifying the article. The url is
+// used when it is known, otherwise the source and the title are.
+func fingerprint(a Article) string {
+	if a.URL != "" {
+		return shortHash(a.URL)
+	}
+
+	return shortHash(a.Source.ID + "\n" + a.Source.Name + "\n" + a.Title)
+}
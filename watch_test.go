@@ -0,0 +1,432 @@
+package newsapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock whose timers fire immediately, advancing the
+// current time by the waited duration.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return fc.now
+}
+
+func (fc *fakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.now = fc.now.Add(d)
+	fc.waits = append(fc.waits, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- fc.now
+
+	return ch
+}
+
+// pollAPI returns a predefined response on every call and cancels the
+// context once all of them are returned. No articles are returned after
+// that.
+type pollAPI struct {
+	stubAPI
+
+	mu           sync.Mutex
+	cancel       context.CancelFunc
+	clock        *fakeClock
+	elapsed      time.Duration
+	responses    [][]Article
+	everything   []EverythingParams
+	topHeadlines []TopHeadlinesParams
+}
+
+func (p *pollAPI) Everything(_ context.Context, pr EverythingParams) ([]Article, uint, error) {
+	p.mu.Lock()
+	p.everything = append(p.everything, pr)
+	p.mu.Unlock()
+
+	return p.respond()
+}
+
+func (p *pollAPI) TopHeadlines(_ context.Context, pr TopHeadlinesParams) ([]Article, uint, error) {
+	p.mu.Lock()
+	p.topHeadlines = append(p.topHeadlines, pr)
+	p.mu.Unlock()
+
+	return p.respond()
+}
+
+func (p *pollAPI) respond() ([]Article, uint, error) {
+	if err := p.call(); err != nil {
+		return nil, 0, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clock != nil {
+		p.clock.mu.Lock()
+		p.clock.now = p.clock.now.Add(p.elapsed)
+		p.clock.mu.Unlock()
+	}
+
+	if len(p.responses) == 0 {
+		return nil, 0, nil
+	}
+
+	articles := p.responses[0]
+	p.responses = p.responses[1:]
+
+	if len(p.responses) == 0 {
+		p.cancel()
+	}
+
+	return articles, uint(len(articles)), nil
+}
+
+// feedAPI serves everything endpoint over a corpus of articles, newest
+// first, publishing the next batch of articles at the start of every
+// poll. The context is cancelled once all batches are published and
+// polled.
+type feedAPI struct {
+	stubAPI
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	articles []Article
+	batches  [][]Article
+	requests []EverythingParams
+}
+
+func (f *feedAPI) Everything(_ context.Context, pr EverythingParams) ([]Article, uint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, pr)
+
+	if pr.Page == 1 {
+		if len(f.batches) == 0 {
+			f.cancel()
+			return nil, 0, nil
+		}
+
+		f.articles = append(f.articles, f.batches[0]...)
+		f.batches = f.batches[1:]
+	}
+
+	var matched []Article
+
+	for _, a := range f.articles {
+		if !a.PublishedAt.Before(pr.From) {
+			matched = append(matched, a)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].PublishedAt.After(matched[j].PublishedAt)
+	})
+
+	offset := int(pr.Page-1) * int(pr.PageSize)
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+
+	end := offset + int(pr.PageSize)
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return matched[offset:end], uint(len(matched)), nil
+}
+
+func testArticle(url string, publishedAt time.Time) Article {
+	return Article{
+		Title:       url,
+		URL:         "https://example.com/" + url,
+		PublishedAt: publishedAt,
+	}
+}
+
+func Test_Watcher_Run(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	a1 := testArticle("1", start)
+	a2 := testArticle("2", start.Add(time.Minute))
+	a3 := testArticle("3", start.Add(2*time.Minute+500*time.Millisecond))
+	a4 := testArticle("4", start.Add(90*time.Second))
+	a5 := testArticle("5", start.Add(10*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{now: start}
+	api := &pollAPI{
+		cancel:  cancel,
+		clock:   clock,
+		elapsed: time.Second,
+		responses: [][]Article{
+			{a2, a1},
+			{a3, a2},
+			{a3, a4, a5, a2},
+			{},
+		},
+	}
+	w := &Watcher{
+		API: api,
+		Everything: &EverythingParams{
+			Query: "test",
+			From:  start.Add(-time.Hour),
+		},
+		Interval: time.Minute,
+		Lookback: time.Minute,
+		Clock:    clock,
+	}
+
+	var emitted []Article
+
+	err := w.Run(ctx, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Article{a1, a2, a3, a4}, emitted)
+	assert.Equal(t, []time.Duration{59 * time.Second, 59 * time.Second, 59 * time.Second}, clock.waits)
+
+	require.Len(t, api.everything, 4)
+	assert.Equal(t, start.Add(-time.Hour), api.everything[0].From)
+	assert.Equal(t, start, api.everything[1].From)
+	assert.Equal(t, start.Add(time.Minute), api.everything[2].From)
+	assert.Equal(t, start.Add(time.Minute), api.everything[3].From)
+
+	for _, pr := range api.everything {
+		assert.Equal(t, "test", pr.Query)
+		assert.Equal(t, SortByPublishedAt, pr.SortBy)
+	}
+
+	// Watched params are not modified.
+	assert.Equal(t, start.Add(-time.Hour), w.Everything.From)
+}
+
+func Test_Watcher_Run_burst(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	var burst []Article
+	for i := 1; i <= 9; i++ {
+		burst = append(burst, testArticle(strconv.Itoa(i), start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := testArticle("0", start)
+	api := &feedAPI{
+		cancel:  cancel,
+		batches: [][]Article{{initial}, burst},
+	}
+	w := &Watcher{
+		API: api,
+		Everything: &EverythingParams{
+			Query:    "test",
+			SortBy:   SortByRelevancy,
+			PageSize: 3,
+		},
+		Clock: &fakeClock{now: start},
+	}
+
+	var emitted []Article
+
+	err := w.Run(ctx, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, append([]Article{initial}, burst...), emitted)
+
+	var pages []uint
+	for _, pr := range api.requests {
+		assert.Equal(t, SortByPublishedAt, pr.SortBy)
+		pages = append(pages, pr.Page)
+	}
+
+	// The burst is paged through until the page with the initial article
+	// is reached.
+	assert.Equal(t, []uint{1, 1, 2, 3, 4, 1}, pages)
+}
+
+func Test_Watcher_Run_initial(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+
+	var backlog []Article
+	for i := 0; i < 50; i++ {
+		backlog = append(backlog, testArticle(strconv.Itoa(i), start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	fresh := []Article{
+		testArticle("a", start.Add(time.Hour)),
+		testArticle("b", start.Add(time.Hour+time.Minute)),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &feedAPI{
+		cancel:  cancel,
+		batches: [][]Article{backlog, fresh},
+	}
+	w := &Watcher{
+		API: api,
+		Everything: &EverythingParams{
+			Query:    "test",
+			PageSize: 5,
+		},
+		SkipInitial: true,
+		Clock:       &fakeClock{now: start},
+	}
+
+	var emitted []Article
+
+	err := w.Run(ctx, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, fresh, emitted)
+
+	var pages []uint
+	for _, pr := range api.requests {
+		pages = append(pages, pr.Page)
+	}
+
+	// The backlog is not paged through by the first poll.
+	assert.Equal(t, []uint{1, 1, 1}, pages)
+}
+
+func Test_Watcher_Run_topHeadlines(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	a1 := testArticle("1", start)
+	a2 := testArticle("2", start.Add(time.Minute))
+	a3 := Article{Title: "3", PublishedAt: start.Add(time.Minute)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &pollAPI{
+		cancel: cancel,
+		responses: [][]Article{
+			{a1},
+			{a2, a1},
+			{a3, a2, a3},
+		},
+	}
+	w := &Watcher{
+		API:          api,
+		TopHeadlines: &TopHeadlinesParams{Country: CountryUnitedStates},
+		SkipInitial:  true,
+		Clock:        &fakeClock{now: start},
+	}
+
+	var emitted []Article
+
+	err := w.Run(ctx, func(a Article) error {
+		emitted = append(emitted, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Article{a2, a3}, emitted)
+	assert.Len(t, api.topHeadlines, 3)
+}
+
+func Test_Watcher_Run_errors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &Watcher{API: &pollAPI{}}
+	assert.Equal(t, ErrInvalidWatchParams, w.Run(ctx, nil))
+
+	w = &Watcher{
+		API:          &pollAPI{},
+		Everything:   &EverythingParams{Query: "test"},
+		TopHeadlines: &TopHeadlinesParams{Country: CountryUnitedStates},
+	}
+	assert.Equal(t, ErrInvalidWatchParams, w.Run(ctx, nil))
+
+	w = &Watcher{
+		API:        &pollAPI{},
+		Everything: &EverythingParams{},
+	}
+	assert.ErrorIs(t, w.Run(ctx, nil), ErrParamsScopeTooBroad)
+
+	w = &Watcher{
+		API: &pollAPI{
+			stubAPI: stubAPI{errs: []error{assert.AnError}},
+		},
+		Everything: &EverythingParams{Query: "test"},
+	}
+	assert.Equal(t, assert.AnError, w.Run(ctx, nil))
+
+	w = &Watcher{
+		API: &pollAPI{
+			cancel:    cancel,
+			responses: [][]Article{{testArticle("1", time.Now())}, {}},
+		},
+		Everything: &EverythingParams{Query: "test"},
+	}
+	assert.Equal(t, assert.AnError, w.Run(ctx, func(Article) error {
+		return assert.AnError
+	}))
+}
+
+func Test_Watcher_Watch(t *testing.T) {
+	start := time.Date(2022, 02, 22, 0, 0, 0, 0, time.UTC)
+	a1 := testArticle("1", start)
+	a2 := testArticle("2", start.Add(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &Watcher{
+		API: &pollAPI{
+			cancel:    func() {},
+			responses: [][]Article{{a1}, {a2, a1}, {}},
+		},
+		Everything: &EverythingParams{Query: "test"},
+		Clock:      &fakeClock{now: start},
+	}
+
+	articles, errs := w.Watch(ctx)
+	assert.Equal(t, a1, <-articles)
+	assert.Equal(t, a2, <-articles)
+
+	cancel()
+
+	for range articles {
+	}
+
+	assert.NoError(t, <-errs)
+
+	w = &Watcher{API: &pollAPI{}}
+
+	articles, errs = w.Watch(context.Background())
+	assert.Equal(t, ErrInvalidWatchParams, <-errs)
+
+	_, ok := <-articles
+	assert.False(t, ok)
+}
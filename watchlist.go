@@ -0,0 +1,260 @@
+package newsapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jellydator/newsapi-go/query"
+)
+
+// WatchlistOption is used to apply optional watchlist configuration.
+type WatchlistOption func(*Watchlist)
+
+// WithWatchlistMaxResults sets the number of results newsapi allows to
+// page through. Packs with more results are split. 100 is default.
+func WithWatchlistMaxResults(n uint) WatchlistOption {
+	return func(wl *Watchlist) {
+		if n > 0 {
+			wl.maxResults = n
+		}
+	}
+}
+
+// Watchlist monitors many terms with few requests. Terms are packed into
+// queries joined with "OR" operator, that stay within the query length
+// limit, and returned articles are matched back to the individual terms
+// locally. Packs with more results than can be paged through are split
+// in halves, and the split packs are kept for the following runs.
+type Watchlist struct {
+	api        API
+	maxResults uint
+	packs      [][]string
+}
+
+// WatchlistResult contains the outcome of a watchlist run.
+type WatchlistResult struct {
+	// Matches specifies the articles matched to every term. Terms
+	// without matches are omitted.
+	Matches map[string][]Article
+
+	// Unmatched specifies the articles that newsapi returned but none of
+	// the terms matched locally, e.g. due to differences in stemming.
+	Unmatched []Article
+
+	// Incomplete specifies the terms whose articles could not be all
+	// retrieved, because even the term alone has more results than can
+	// be paged through.
+	Incomplete []string
+
+	// Requests specifies the number of requests sent.
+	Requests uint
+}
+
+// NewWatchlist packs the terms and creates a fresh instance of watchlist.
+// Empty and duplicate terms are ignored. ErrInvalidQueryLength is
+// returned if any term alone exceeds the query length limit.
+func NewWatchlist(api API, terms []string, opts ...WatchlistOption) (*Watchlist, error) {
+	packs, err := packTerms(terms, _maxQueryLength)
+	if err != nil {
+		return nil, err
+	}
+
+	wl := &Watchlist{
+		api:        api,
+		maxResults: _defaultHarvestMaxResults,
+		packs:      packs,
+	}
+
+	for _, opt := range opts {
+		opt(wl)
+	}
+
+	return wl, nil
+}
+
+// Packs returns the terms of every packed query.
+func (wl *Watchlist) Packs() [][]string {
+	res := make([][]string, 0, len(wl.packs))
+	for _, pack := range wl.packs {
+		res = append(res, append([]string(nil), pack...))
+	}
+
+	return res
+}
+
+// Run runs every packed query with the parameters and matches returned
+// articles to the terms. The query of the parameters must be empty, as
+// it is replaced by the packed queries; SearchIn is used for local
+// matching too. Run must not be called concurrently.
+func (wl *Watchlist) Run(ctx context.Context, pr EverythingParams) (*WatchlistResult, error) {
+	if pr.Query != "" {
+		return nil, ErrWatchlistQuery
+	}
+
+	if pr.PageSize == 0 {
+		pr.PageSize = _harvestPageSize
+	}
+
+	res := &WatchlistResult{
+		Matches: make(map[string][]Article),
+	}
+
+	queue := wl.Packs()
+	packs := make([][]string, 0, len(queue))
+	seen := make(map[string]struct{})
+
+	for len(queue) > 0 {
+		pack := queue[0]
+		queue = queue[1:]
+
+		pr.Query = query.Terms(pack...).String()
+		pr.Page = 1
+
+		if err := pr.Validate(); err != nil {
+			return nil, err
+		}
+
+		page, total, err := wl.api.Everything(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Requests++
+
+		if total > wl.maxResults && len(pack) > 1 {
+			mid := len(pack) / 2
+			queue = append([][]string{pack[:mid], pack[mid:]}, queue...)
+
+			continue
+		}
+
+		packs = append(packs, pack)
+
+		articles, err := wl.page(ctx, pr, page, total, res)
+		if err != nil {
+			return nil, err
+		}
+
+		if uint(len(articles)) < total {
+			res.Incomplete = append(res.Incomplete, pack...)
+		}
+
+		if err = res.match(pack, articles, pr.SearchIn, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	wl.packs = packs
+
+	return res, nil
+}
+
+// page retrieves the remaining pages of the packed query, up to the
+// number of results newsapi allows to page through.
+func (wl *Watchlist) page(ctx context.Context, pr EverythingParams, page []Article, total uint, res *WatchlistResult) ([]Article, error) {
+	limit := total
+	if limit > wl.maxResults {
+		limit = wl.maxResults
+	}
+
+	articles := append([]Article(nil), page...)
+
+	for uint(len(articles)) < limit && uint(len(page)) == pr.PageSize {
+		pr.Page++
+
+		var err error
+
+		page, _, err = wl.api.Everything(ctx, pr)
+		if err != nil {
+			if errors.Is(err, ErrMaximumResultsReached) {
+				break
+			}
+
+			return nil, err
+		}
+
+		res.Requests++
+		articles = append(articles, page...)
+	}
+
+	return articles, nil
+}
+
+// match matches the articles of the packed query to its terms. Articles
+// already seen in other packs are not added to the unmatched ones again.
+func (res *WatchlistResult) match(pack []string, articles []Article, searchIn SearchIn, seen map[string]struct{}) error {
+	var si []SearchIn
+	if searchIn != "" {
+		si = append(si, searchIn)
+	}
+
+	matchers := make([]*ArticleMatcher, 0, len(pack))
+
+	for _, term := range pack {
+		am, err := NewArticleMatcher(query.Term{Value: term}.String(), si...)
+		if err != nil {
+			return err
+		}
+
+		matchers = append(matchers, am)
+	}
+
+	for _, a := range articles {
+		matched := false
+
+		for i, am := range matchers {
+			if am.Match(a).Matched {
+				matched = true
+				res.Matches[pack[i]] = append(res.Matches[pack[i]], a)
+			}
+		}
+
+		if _, ok := seen[a.URL]; ok {
+			continue
+		}
+
+		seen[a.URL] = struct{}{}
+
+		if !matched {
+			res.Unmatched = append(res.Unmatched, a)
+		}
+	}
+
+	return nil
+}
+
+// packTerms packs the terms into groups whose "OR" queries do not
+// exceed the length limit.
+func packTerms(terms []string, limit int) ([][]string, error) {
+	var (
+		packs [][]string
+		pack  []string
+	)
+
+	seen := make(map[string]struct{}, len(terms))
+
+	for _, term := range terms {
+		if _, ok := seen[term]; ok || term == "" {
+			continue
+		}
+
+		seen[term] = struct{}{}
+
+		if len(query.Term{Value: term}.String()) > limit {
+			return nil, ErrInvalidQueryLength
+		}
+
+		if len(query.Terms(append(pack, term)...).String()) > limit {
+			packs = append(packs, pack)
+			pack = nil
+		}
+
+		pack = append(pack, term)
+	}
+
+	if len(pack) > 0 {
+		packs = append(packs, pack)
+	}
+
+	return packs, nil
+}
@@ -0,0 +1,177 @@
+package newsapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jellydator/newsapi-go/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryAPI serves everything endpoint by matching the query against a
+// corpus of articles locally.
+type queryAPI struct {
+	stubAPI
+
+	mu       sync.Mutex
+	articles []Article
+	queries  []string
+}
+
+func (q *queryAPI) Everything(_ context.Context, pr EverythingParams) ([]Article, uint, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queries = append(q.queries, pr.Query)
+
+	if err := q.call(); err != nil {
+		return nil, 0, err
+	}
+
+	am, err := NewArticleMatcher(pr.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matched := am.Filter(q.articles)
+
+	offset := int(pr.Page-1) * int(pr.PageSize)
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+
+	end := offset + int(pr.PageSize)
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return matched[offset:end], uint(len(matched)), nil
+}
+
+func Test_NewWatchlist(t *testing.T) {
+	wl, err := NewWatchlist(&stubAPI{}, []string{"Apple Inc", "", "Microsoft", "Apple Inc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Apple Inc", "Microsoft"}}, wl.Packs())
+
+	_, err = NewWatchlist(&stubAPI{}, []string{strings.Repeat("a", 501)})
+	assert.Equal(t, ErrInvalidQueryLength, err)
+}
+
+func Test_packTerms(t *testing.T) {
+	packs, err := packTerms([]string{"aaaa", "bbbb", "cc dd", "e"}, 16)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"aaaa", "bbbb"}, {"cc dd", "e"}}, packs)
+
+	packs, err = packTerms([]string{"aaaa", "bbbb"}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"aaaa"}, {"bbbb"}}, packs)
+
+	_, err = packTerms([]string{"a b"}, 4)
+	assert.Equal(t, ErrInvalidQueryLength, err)
+
+	var terms []string
+	for i := 0; i < 2000; i++ {
+		terms = append(terms, "company "+strconv.Itoa(i))
+	}
+
+	packs, err = packTerms(terms, _maxQueryLength)
+	require.NoError(t, err)
+
+	var packed []string
+
+	for i, pack := range packs {
+		q := query.Terms(pack...).String()
+		assert.True(t, len(q) <= _maxQueryLength)
+
+		if i < len(packs)-1 {
+			assert.True(t, len(q) > _maxQueryLength-20)
+		}
+
+		packed = append(packed, pack...)
+	}
+
+	assert.Equal(t, terms[:len(packed)], packed)
+	assert.Len(t, packed, 2000)
+}
+
+func Test_Watchlist_Run(t *testing.T) {
+	api := &queryAPI{
+		articles: []Article{
+			{URL: "1", Title: "Apple releases a phone"},
+			{URL: "2", Title: "Microsoft and Apple Inc partner up"},
+			{URL: "3", Title: "Nothing to see", Description: "microsoft"},
+			{URL: "4", Title: "Google announces", Content: "Alphabet Inc"},
+			{URL: "5", Title: "Orange"},
+			{URL: "6", Title: "Google again"},
+			{URL: "7", Title: "Google once more"},
+			{URL: "8", Title: "Google yet again"},
+		},
+	}
+
+	wl, err := NewWatchlist(api, []string{"Apple", "Microsoft", "Google", "Orange", "Amazon"}, WithWatchlistMaxResults(4))
+	require.NoError(t, err)
+
+	_, err = wl.Run(context.Background(), EverythingParams{Query: "test"})
+	assert.Equal(t, ErrWatchlistQuery, err)
+
+	res, err := wl.Run(context.Background(), EverythingParams{
+		SearchIn: SearchInTitle,
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]Article{
+		"Apple":     {api.articles[0], api.articles[1]},
+		"Microsoft": {api.articles[1]},
+		"Google":    {api.articles[3], api.articles[5], api.articles[6], api.articles[7]},
+		"Orange":    {api.articles[4]},
+	}, res.Matches)
+	assert.Equal(t, []Article{api.articles[2]}, res.Unmatched)
+	assert.Empty(t, res.Incomplete)
+	assert.Equal(t, uint(7), res.Requests)
+
+	assert.Equal(t, []string{
+		"Apple OR Microsoft OR Google OR Orange OR Amazon",
+		"Apple OR Microsoft",
+		"Apple OR Microsoft",
+		"Google OR Orange OR Amazon",
+		"Google",
+		"Google",
+		"Orange OR Amazon",
+	}, api.queries)
+	assert.Equal(t, [][]string{{"Apple", "Microsoft"}, {"Google"}, {"Orange", "Amazon"}}, wl.Packs())
+
+	// Split packs are kept.
+	api.queries = nil
+
+	res, err = wl.Run(context.Background(), EverythingParams{SearchIn: SearchInTitle})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), res.Requests)
+	assert.Len(t, api.queries, 3)
+
+	// Terms with too many results alone are incomplete.
+	wl, err = NewWatchlist(api, []string{"Google"}, WithWatchlistMaxResults(2))
+	require.NoError(t, err)
+
+	res, err = wl.Run(context.Background(), EverythingParams{PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Google"}, res.Incomplete)
+	assert.Len(t, res.Matches["Google"], 2)
+
+	api.errs = []error{nil, assert.AnError}
+
+	_, err = wl.Run(context.Background(), EverythingParams{PageSize: 1})
+	assert.Equal(t, assert.AnError, err)
+
+	api.errs = []error{assert.AnError}
+
+	_, err = wl.Run(context.Background(), EverythingParams{PageSize: 1})
+	assert.Equal(t, assert.AnError, err)
+
+	_, err = wl.Run(context.Background(), EverythingParams{Language: "xx"})
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+}